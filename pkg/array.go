@@ -0,0 +1,104 @@
+package vgo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// elementType splits the `array<type>` shorthand into `array` and the element type.
+func elementType(typ string) (string, string) {
+	if strings.HasPrefix(typ, "array<") && strings.HasSuffix(typ, ">") {
+		return "array", typ[len("array<") : len(typ)-1]
+	}
+	return typ, ""
+}
+
+func prepareCount(args []string) (interface{}, error) {
+	count, err := strconv.Atoi(args[0])
+	if err != nil || count < 0 {
+		return nil, fmt.Errorf("bad item count %q", args[0])
+	}
+	return count, nil
+}
+
+// countRule returns an array rule that fails when fails reports true for the number of items.
+func countRule(key string, fails func(count, limit int) bool) validatorFunc {
+	return func(context *phaseContext, obj subjectObj) error {
+		items, ok := context.value.([]interface{})
+		if !ok {
+			return nil
+		}
+		limit := context.prepared.(int)
+		if fails(len(items), limit) {
+			context.hasError = true
+			context.err = context.translate(key, context.attribute(context.name), limit)
+		}
+		return nil
+	}
+}
+
+func arrayValidators() map[string]validatorFunc {
+	return map[string]validatorFunc{
+		"minItems": countRule("array.minItems", func(count, limit int) bool {
+			return count < limit
+		}),
+		"maxItems": countRule("array.maxItems", func(count, limit int) bool {
+			return count > limit
+		}),
+		"sizeItems": countRule("array.sizeItems", func(count, limit int) bool {
+			return count != limit
+		}),
+		"distinct": func(context *phaseContext, obj subjectObj) error {
+			items, ok := context.value.([]interface{})
+			if !ok {
+				return nil
+			}
+			var key []string
+			if len(context.args) > 0 {
+				key = splitPath(context.args[0])
+			}
+			seen := make(map[string]bool, len(items))
+			for _, item := range items {
+				value, ok := item, true
+				for _, segment := range key {
+					if value, ok = childValue(value, segment); !ok {
+						break
+					}
+				}
+				if !ok {
+					continue
+				}
+				id := fmt.Sprintf("%#v", value)
+				if seen[id] {
+					context.hasError = true
+					context.err = context.translate("array.distinct", context.attribute(context.name))
+					return nil
+				}
+				seen[id] = true
+			}
+			return nil
+		},
+		"containsValue": func(context *phaseContext, obj subjectObj) error {
+			items, ok := context.value.([]interface{})
+			if !ok {
+				return nil
+			}
+			for _, arg := range context.args {
+				found := false
+				for _, item := range items {
+					if matchValue(item, arg) {
+						found = true
+						break
+					}
+				}
+				if !found {
+					context.hasError = true
+					context.err = context.translate("array.containsValue", context.attribute(context.name), strings.Join(context.args, ", "))
+					return nil
+				}
+			}
+			return nil
+		},
+	}
+}
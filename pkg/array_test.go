@@ -0,0 +1,88 @@
+package vgo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestArrayRules(t *testing.T) {
+	tests := []struct {
+		rule  string
+		value string
+		code  string
+	}{
+		{"a(array) minItems(2)", `[1, 2]`, ""},
+		{"a(array) minItems(2)", `[1]`, "array.minItems"},
+		{"a(array) maxItems(2)", `[1, 2, 3]`, "array.maxItems"},
+		{"a(array) sizeItems(0)", `[]`, ""},
+		{"a(array) sizeItems(2)", `[1]`, "array.sizeItems"},
+		{"a(array) distinct", `[1, "1", true]`, ""},
+		{"a(array) distinct", `["x", "y", "x"]`, "array.distinct"},
+		{"a(array) distinct", `[{"k": 1}, {"k": 1}]`, "array.distinct"},
+		{"a(array) distinct(email)", `[{"email": "a"}, {"email": "b"}, {}]`, ""},
+		{"a(array) distinct(email)", `[{"email": "a"}, {"email": "a", "x": 1}]`, "array.distinct"},
+		{"a(array) distinct(user.id)", `[{"user": {"id": 1}}, {"user": {"id": 1}}]`, "array.distinct"},
+		{"a(array<uint>) distinct", `[1, "1"]`, "array.distinct"},
+		{"a(array) containsValue(x,y)", `["y", "x", "z"]`, ""},
+		{"a(array) containsValue(x,y)", `["x"]`, "array.containsValue"},
+		{"a(array<uint>) containsValue(1)", `["1"]`, ""},
+	}
+	for _, test := range tests {
+		_, err := ValidateJson(`{"a": `+test.value+`}`, []string{test.rule})
+		var code string
+		if errs, ok := err.(ValidationErrors); ok {
+			code = errs[0].Rule
+		} else if err != nil {
+			t.Fatalf("%s: %v", test.rule, err)
+		}
+		if code != test.code {
+			t.Errorf("%s with %s: got %q, want %q", test.rule, test.value, code, test.code)
+		}
+	}
+}
+
+func TestArrayElements(t *testing.T) {
+	tests := []struct {
+		rule  string
+		value string
+		want  interface{}
+		errs  map[string]string
+	}{
+		{"a(array<string>)", `["x", "y"]`, []interface{}{"x", "y"}, nil},
+		{"a(array<uint>)", `[1, "2", "۳"]`, []interface{}{uint64(1), uint64(2), uint64(3)}, nil},
+		{"a(array<string>)", `["x", 1, "y", false]`, nil, map[string]string{"a.1": "type.string", "a.3": "type.string"}},
+		{"a(array<int>) maxItems(1)", `[1, 2]`, nil, map[string]string{"a": "array.maxItems"}},
+		{"a(array<bool(lenient)>)", `["yes", 0]`, []interface{}{true, false}, nil},
+	}
+	for _, test := range tests {
+		values, err := ValidateJson(`{"a": `+test.value+`}`, []string{test.rule}, AllErrors())
+		if errs := failedRules(t, err); !reflect.DeepEqual(errs, test.errs) {
+			t.Errorf("%s with %s: got %v, want %v", test.rule, test.value, errs, test.errs)
+			continue
+		}
+		if test.errs == nil && !reflect.DeepEqual(values["a"], test.want) {
+			t.Errorf("%s with %s: got %#v, want %#v", test.rule, test.value, values["a"], test.want)
+		}
+	}
+}
+
+func TestArrayMessages(t *testing.T) {
+	tests := []struct {
+		rule string
+		want string
+	}{
+		{"a(array) minItems(3)", "The a must have at least 3 items."},
+		{"a(array) distinct", "The a field has a duplicate value."},
+		{"a(array) containsValue(x,y)", "The a must contain x, y."},
+	}
+	for _, test := range tests {
+		_, err := ValidateJson(`{"a": [1, 1]}`, []string{test.rule}, WithLocale(English))
+		errs, ok := err.(ValidationErrors)
+		if !ok {
+			t.Fatalf("%s: got %v", test.rule, err)
+		}
+		if errs[0].Message != test.want {
+			t.Errorf("%s: got %q, want %q", test.rule, errs[0].Message, test.want)
+		}
+	}
+}
@@ -0,0 +1,415 @@
+package vgo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+var fileType = reflect.TypeOf(File{})
+
+var structSchemas sync.Map
+
+type structSchema struct {
+	schema *Schema
+	err    error
+}
+
+// ValidateInto decodes a JSON body, validates it with the rules found in the `vgo` tags of dst and assigns the
+// converted values to dst, which must be a pointer to a struct.
+//
+// The field type is inferred from the Go type and can be overridden by starting the tag with a type name, e.g.
+// `vgo:"image required"`. Field names are taken from the `json` tag when present, `vgo:"-"` skips a field.
+func ValidateInto(body []byte, dst interface{}, opts ...Option) error {
+	target := reflect.ValueOf(dst)
+	if target.Kind() != reflect.Ptr || target.IsNil() || target.Elem().Kind() != reflect.Struct {
+		return errors.New("vgo: ValidateInto needs a non nil pointer to a struct")
+	}
+	schema, err := StructSchema(target.Elem().Type())
+	if err != nil {
+		return err
+	}
+	data, err := schema.decodeJSON(body)
+	if err != nil {
+		return ErrMalformedRequest
+	}
+	values, err := schema.Check(data, opts...)
+	if err != nil {
+		return err
+	}
+	err = assignValue(target.Elem(), values, nil)
+	if errs, ok := err.(ValidationErrors); ok {
+		key := errs[0].Rule
+		if strings.HasSuffix(key, ".overflow") {
+			key = "int.overflow"
+		}
+		locale := schema.options.with(opts).translator()
+		errs[0].Message = locale.Translate(key, locale.Attribute(errs[0].Field))
+	}
+	return err
+}
+
+// StructSchema compiles the `vgo` tags of a struct type, the result is cached per type.
+func StructSchema(typ reflect.Type) (*Schema, error) {
+	if cached, ok := structSchemas.Load(typ); ok {
+		return cached.(*structSchema).schema, cached.(*structSchema).err
+	}
+	guards := map[string]int{}
+	registryMutex.RLock()
+	rules, err := structRules(typ, "", guards)
+	registryMutex.RUnlock()
+	compiled := &structSchema{err: err}
+	if err == nil {
+		compiled.schema, compiled.err = Compile(rules)
+	}
+	if compiled.err == nil {
+		for _, field := range compiled.schema.fields {
+			field.guard = guards[field.name]
+		}
+	}
+	structSchemas.Store(typ, compiled)
+	return compiled.schema, compiled.err
+}
+
+// structRules builds the rules of a struct, guards records for fields below an optional pointer to a struct
+// the length of the path that has to be present for them to be validated.
+func structRules(typ reflect.Type, prefix string, guards map[string]int) ([]string, error) {
+	var rules, promoted []string
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("vgo")
+		if tag == "-" {
+			continue
+		}
+		if embedded, ok := embeddedStruct(field); ok {
+			children, err := structRules(embedded, prefix, guards)
+			if err != nil {
+				return nil, err
+			}
+			promoted = append(promoted, children...)
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		name := fieldKey(field)
+		if name == "" {
+			continue
+		}
+		fieldRules, err := typeRules(field.Type, prefix+name, tag, guards)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, fieldRules...)
+	}
+	names := directKeys(typ)
+	for _, rule := range promoted {
+		name := strings.TrimPrefix(strings.SplitN(rule, "(", 2)[0], prefix)
+		if !names[splitPath(name)[0]] {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+// embeddedStruct returns the struct type of an anonymous field without a json name, its fields are promoted to the
+// object of the parent like encoding/json does.
+func embeddedStruct(field reflect.StructField) (reflect.Type, bool) {
+	if !field.Anonymous {
+		return nil, false
+	}
+	if tag, ok := field.Tag.Lookup("json"); ok && strings.Split(tag, ",")[0] != "" {
+		return nil, false
+	}
+	typ := field.Type
+	if typ.Kind() == reflect.Ptr {
+		if field.PkgPath != "" {
+			return nil, false
+		}
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct || typ == timeType || typ == fileType {
+		return nil, false
+	}
+	return typ, true
+}
+
+// directKeys returns the keys of the fields declared on typ itself, they hide the promoted fields of the same name.
+func directKeys(typ reflect.Type) map[string]bool {
+	keys := map[string]bool{}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if _, ok := embeddedStruct(field); ok || field.PkgPath != "" || field.Tag.Get("vgo") == "-" {
+			continue
+		}
+		if key := fieldKey(field); key != "" {
+			keys[key] = true
+		}
+	}
+	return keys
+}
+
+func typeRules(typ reflect.Type, name string, tag string, guards map[string]int) ([]string, error) {
+	optional := false
+	for typ.Kind() == reflect.Ptr && typ != reflect.PtrTo(fileType) {
+		typ = typ.Elem()
+		optional = true
+	}
+	inferred := inferType(typ)
+	tag = strings.TrimSpace(tag)
+	if words := strings.Fields(tag); len(words) > 0 && knownTypes[strings.SplitN(words[0], "(", 2)[0]] {
+		inferred = words[0]
+		tag = strings.TrimSpace(tag[len(words[0]):])
+	}
+	rules := []string{strings.TrimSpace(fmt.Sprintf("%s(%s) %s", name, inferred, tag))}
+	switch {
+	case inferred == "object" && typ.Kind() == reflect.Struct:
+		children, err := structRules(typ, name+".", guards)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, children...)
+		if optional {
+			for _, child := range children {
+				child = strings.SplitN(child, "(", 2)[0]
+				if guards[child] == 0 {
+					guards[child] = len(splitPath(name))
+				}
+			}
+		}
+	case inferred == "array":
+		elem := typ.Elem()
+		for elem.Kind() == reflect.Ptr && elem != reflect.PtrTo(fileType) {
+			elem = elem.Elem()
+		}
+		if elem.Kind() == reflect.Interface {
+			break
+		}
+		children, err := typeRules(elem, name+".*", "", guards)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, children...)
+	}
+	return rules, nil
+}
+
+func inferType(typ reflect.Type) string {
+	switch {
+	case typ == timeType:
+		return "date"
+	case typ == fileType || typ == reflect.PtrTo(fileType):
+		return "file"
+	}
+	switch typ.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "int"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "uint"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Struct, reflect.Map:
+		return "object"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	}
+	return "any"
+}
+
+func fieldKey(field reflect.StructField) string {
+	if tag, ok := field.Tag.Lookup("json"); ok {
+		name := strings.Split(tag, ",")[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// assignValue stores a validated value in dst, a number that does not fit dst is reported as ValidationErrors at path.
+func assignValue(dst reflect.Value, value interface{}, path []string) error {
+	if value == nil {
+		return nil
+	}
+	src := reflect.ValueOf(value)
+	if src.Type().AssignableTo(dst.Type()) {
+		dst.Set(src)
+		return nil
+	}
+	if src.Kind() == reflect.Ptr && src.Elem().Type().AssignableTo(dst.Type()) {
+		dst.Set(src.Elem())
+		return nil
+	}
+	switch dst.Kind() {
+	case reflect.Ptr:
+		elem := reflect.New(dst.Type().Elem())
+		if err := assignValue(elem.Elem(), value, path); err != nil {
+			return err
+		}
+		dst.Set(elem)
+		return nil
+	case reflect.Struct:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			break
+		}
+		typ := dst.Type()
+		var promoted map[string]interface{}
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if field.Tag.Get("vgo") == "-" {
+				continue
+			}
+			if _, ok := embeddedStruct(field); ok {
+				if promoted == nil {
+					keys := directKeys(typ)
+					promoted = make(map[string]interface{}, len(obj))
+					for key, item := range obj {
+						if !keys[key] {
+							promoted[key] = item
+						}
+					}
+				}
+				embedded := dst.Field(i)
+				if embedded.Kind() == reflect.Ptr {
+					if embedded.IsNil() {
+						embedded.Set(reflect.New(embedded.Type().Elem()))
+					}
+					embedded = embedded.Elem()
+				}
+				if err := assignValue(embedded, promoted, path); err != nil {
+					return err
+				}
+				continue
+			}
+			if field.PkgPath != "" {
+				continue
+			}
+			key := fieldKey(field)
+			item, ok := obj[key]
+			if !ok {
+				continue
+			}
+			if err := assignValue(dst.Field(i), item, append(path[:len(path):len(path)], key)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Slice:
+		items, ok := value.([]interface{})
+		if !ok {
+			break
+		}
+		out := reflect.MakeSlice(dst.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := assignValue(out.Index(i), item, append(path[:len(path):len(path)], strconv.Itoa(i))); err != nil {
+				return err
+			}
+		}
+		dst.Set(out)
+		return nil
+	case reflect.Array:
+		items, ok := value.([]interface{})
+		if !ok {
+			break
+		}
+		for i := 0; i < len(items) && i < dst.Len(); i++ {
+			if err := assignValue(dst.Index(i), items[i], append(path[:len(path):len(path)], strconv.Itoa(i))); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		obj, ok := value.(map[string]interface{})
+		if !ok || dst.Type().Key().Kind() != reflect.String {
+			break
+		}
+		out := reflect.MakeMapWithSize(dst.Type(), len(obj))
+		for key, item := range obj {
+			elem := reflect.New(dst.Type().Elem()).Elem()
+			if err := assignValue(elem, item, append(path[:len(path):len(path)], key)); err != nil {
+				return err
+			}
+			out.SetMapIndex(reflect.ValueOf(key).Convert(dst.Type().Key()), elem)
+		}
+		dst.Set(out)
+		return nil
+	default:
+		if src.Type().ConvertibleTo(dst.Type()) && src.Kind() != reflect.String {
+			if code := rangeError(dst, src); code != "" {
+				return ValidationErrors{{Field: strings.Join(path, "."), Rule: code, Value: value}}
+			}
+			dst.Set(src.Convert(dst.Type()))
+			return nil
+		}
+	}
+	return fmt.Errorf("%s: can not assign %T to %s", strings.Join(path, "."), value, dst.Type())
+}
+
+// rangeError returns the error code of a number that can not be converted to the kind of dst without loss.
+func rangeError(dst, src reflect.Value) string {
+	var num float64
+	switch src.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		num = float64(src.Int())
+		if isInt(dst) && !dst.OverflowInt(src.Int()) || isUint(dst) && src.Int() >= 0 && !dst.OverflowUint(uint64(src.Int())) {
+			return ""
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		num = float64(src.Uint())
+		if isUint(dst) && !dst.OverflowUint(src.Uint()) || isInt(dst) && src.Uint() <= math.MaxInt64 && !dst.OverflowInt(int64(src.Uint())) {
+			return ""
+		}
+	case reflect.Float32, reflect.Float64:
+		num = src.Float()
+		if (isInt(dst) || isUint(dst)) && num != math.Trunc(num) {
+			return "type.int"
+		}
+		if isInt(dst) && num >= math.MinInt64 && num < math.MaxInt64 && !dst.OverflowInt(int64(num)) ||
+			isUint(dst) && num >= 0 && num < math.MaxUint64 && !dst.OverflowUint(uint64(num)) {
+			return ""
+		}
+	default:
+		return ""
+	}
+	switch {
+	case isInt(dst):
+		return "int.overflow"
+	case isUint(dst):
+		return "uint.overflow"
+	case dst.Kind() == reflect.Float32 || dst.Kind() == reflect.Float64:
+		if dst.OverflowFloat(num) {
+			return "number.overflow"
+		}
+	}
+	return ""
+}
+
+func isInt(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+func isUint(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package vgo
+
+import (
+	"testing"
+)
+
+func TestValidateIntoRange(t *testing.T) {
+	type Item struct {
+		Count uint8 `json:"count"`
+	}
+	type Form struct {
+		Age   int8    `json:"age"`
+		U     uint8   `json:"u"`
+		Ratio float32 `json:"ratio"`
+		Score int     `json:"score" vgo:"number"`
+		Items []Item  `json:"items"`
+	}
+	tests := []struct {
+		body  string
+		field string
+		code  string
+	}{
+		{`{"age": 127, "u": 255, "ratio": 0.5, "score": 3, "items": [{"count": 1}]}`, "", ""},
+		{`{"age": 300}`, "age", "int.overflow"},
+		{`{"age": -129}`, "age", "int.overflow"},
+		{`{"u": 300}`, "u", "uint.overflow"},
+		{`{"ratio": 1e300}`, "ratio", "number.overflow"},
+		{`{"score": 3.7}`, "score", "type.int"},
+		{`{"items": [{"count": 1}, {"count": 256}]}`, "items.1.count", "uint.overflow"},
+	}
+	for _, test := range tests {
+		var form Form
+		err := ValidateInto([]byte(test.body), &form, WithLocale(English))
+		if test.code == "" {
+			if err != nil {
+				t.Errorf("%s: %v", test.body, err)
+			}
+			continue
+		}
+		errs, ok := err.(ValidationErrors)
+		if !ok || len(errs) != 1 || errs[0].Field != test.field || errs[0].Rule != test.code || errs[0].Message == "" {
+			t.Errorf("%s: got %v", test.body, err)
+		}
+	}
+}
+
+func TestValidateInto(t *testing.T) {
+	type Address struct {
+		City string `json:"city" vgo:"required"`
+	}
+	type Form struct {
+		Name    string   `json:"name" vgo:"required min(2)"`
+		ID      uint64   `json:"id"`
+		Tags    []string `json:"tags"`
+		Address *Address `json:"address"`
+	}
+	var form Form
+	if err := ValidateInto([]byte(`{"name": "ab", "id": 9007199254740993, "tags": ["x"]}`), &form); err != nil {
+		t.Fatal(err)
+	}
+	if form.Name != "ab" || form.ID != 9007199254740993 || len(form.Tags) != 1 || form.Address != nil {
+		t.Errorf("got %+v", form)
+	}
+	err := ValidateInto([]byte(`{"name": "ab", "address": {}}`), &form)
+	if errs, ok := err.(ValidationErrors); !ok || errs[0].Field != "address.city" {
+		t.Errorf("got %v", err)
+	}
+}
+
+type BindBase struct {
+	ID   int64  `json:"id" vgo:"required"`
+	Kind string `json:"kind"`
+}
+
+type BindAudit struct {
+	By string `json:"by" vgo:"required"`
+}
+
+type bindMeta struct {
+	At string `json:"at"`
+}
+
+func TestValidateIntoEmbedded(t *testing.T) {
+	type Form struct {
+		BindBase
+		*BindAudit
+		Meta bindMeta `json:"meta"`
+		Kind int      `json:"kind"`
+	}
+	var form Form
+	body := `{"id": 5, "by": "me", "kind": 2, "meta": {"at": "x"}}`
+	if err := ValidateInto([]byte(body), &form, WithLocale(English)); err != nil {
+		t.Fatal(err)
+	}
+	if form.ID != 5 || form.BindAudit == nil || form.By != "me" || form.Kind != 2 || form.BindBase.Kind != "" || form.Meta.At != "x" {
+		t.Errorf("got %+v", form)
+	}
+	err := ValidateInto([]byte(`{"by": "me"}`), &form, WithLocale(English))
+	if errs, ok := err.(ValidationErrors); !ok || errs[0].Field != "id" || errs[0].Rule != "required" {
+		t.Errorf("got %v", err)
+	}
+	type Named struct {
+		BindBase `json:"base"`
+	}
+	var named Named
+	if err := ValidateInto([]byte(`{"base": {"id": 7}}`), &named); err != nil || named.ID != 7 {
+		t.Errorf("named: got %+v, %v", named, err)
+	}
+}
@@ -0,0 +1,95 @@
+package vgo
+
+import (
+	"encoding/base64"
+	"errors"
+	"mime"
+	"net/url"
+	"strings"
+)
+
+type dataURI struct {
+	// mediaType is empty when the URI does not declare one.
+	mediaType string
+	params    map[string]string
+	data      []byte
+}
+
+var errDataURI = errors.New("malformed data uri")
+
+// parseDataURI parses an RFC 2397 `data:[<mediatype>][;base64],<data>` URI. Values without the `data:` scheme are
+// decoded as bare base64. Base64 may use the standard or URL safe alphabet, with or without padding. An empty payload
+// is malformed.
+func parseDataURI(value string) (*dataURI, error) {
+	if len(value) < 5 || !strings.EqualFold(value[:5], "data:") {
+		data, err := decodeBase64(value)
+		if err != nil {
+			return nil, err
+		}
+		return &dataURI{data: data}, nil
+	}
+	comma := strings.IndexByte(value, ',')
+	if comma < 0 {
+		return nil, errDataURI
+	}
+	uri := &dataURI{params: map[string]string{}}
+	header := strings.Split(value[5:comma], ";")
+	encoded := false
+	if last := len(header) - 1; last > 0 && strings.EqualFold(strings.TrimSpace(header[last]), "base64") {
+		encoded = true
+		header = header[:last]
+	}
+	if typ := strings.TrimSpace(header[0]); typ != "" {
+		typ, _, err := mime.ParseMediaType(typ)
+		if err != nil || !strings.Contains(typ, "/") {
+			return nil, errDataURI
+		}
+		uri.mediaType = typ
+	}
+	for _, param := range header[1:] {
+		parts := strings.SplitN(param, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, errDataURI
+		}
+		val, err := url.PathUnescape(strings.Trim(strings.TrimSpace(parts[1]), `"`))
+		if err != nil {
+			return nil, errDataURI
+		}
+		uri.params[strings.ToLower(strings.TrimSpace(parts[0]))] = val
+	}
+	payload, err := url.PathUnescape(value[comma+1:])
+	if err != nil || payload == "" {
+		return nil, errDataURI
+	}
+	if !encoded {
+		uri.data = []byte(payload)
+		return uri, nil
+	}
+	uri.data, err = decodeBase64(payload)
+	if err != nil {
+		return nil, err
+	}
+	return uri, nil
+}
+
+func decodeBase64(value string) ([]byte, error) {
+	value = strings.Map(func(char rune) rune {
+		if char == ' ' || char == '\n' || char == '\r' || char == '\t' {
+			return -1
+		}
+		return char
+	}, value)
+	value = strings.TrimRight(value, "=")
+	if value == "" {
+		return nil, errDataURI
+	}
+	encoding := base64.RawStdEncoding
+	if strings.ContainsAny(value, "-_") {
+		encoding = base64.RawURLEncoding
+	}
+	data, err := encoding.DecodeString(value)
+	if err != nil {
+		return nil, errDataURI
+	}
+	return data, nil
+}
@@ -0,0 +1,80 @@
+package vgo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDataURI(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		typ    string
+		params map[string]string
+		data   string
+	}{
+		{"base64", "data:image/png;base64,aGVsbG8=", "image/png", map[string]string{}, "hello"},
+		{"upper case scheme", "DATA:text/plain;BASE64,aGVsbG8=", "text/plain", map[string]string{}, "hello"},
+		{"unpadded", "data:text/plain;base64,aGVsbG8", "text/plain", map[string]string{}, "hello"},
+		{"url safe", "data:application/octet-stream;base64,-_8", "application/octet-stream", map[string]string{}, "\xfb\xff"},
+		{"standard alphabet", "data:application/octet-stream;base64,+/8=", "application/octet-stream", map[string]string{}, "\xfb\xff"},
+		{"line breaks", "data:text/plain;base64,aGVs\nbG8=", "text/plain", map[string]string{}, "hello"},
+		{"parameters", `data:text/plain;charset=utf-8;name="a%20b.txt";base64,aGVsbG8=`, "text/plain",
+			map[string]string{"charset": "utf-8", "name": "a b.txt"}, "hello"},
+		{"percent encoded", "data:text/plain,hello%20world", "text/plain", map[string]string{}, "hello world"},
+		{"no media type", "data:;base64,aGVsbG8=", "", map[string]string{}, "hello"},
+		{"no media type nor base64", "data:,hi", "", map[string]string{}, "hi"},
+		{"bare base64", "aGVsbG8=", "", nil, "hello"},
+	}
+	for _, test := range tests {
+		uri, err := parseDataURI(test.value)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if uri.mediaType != test.typ || !reflect.DeepEqual(uri.params, test.params) || string(uri.data) != test.data {
+			t.Errorf("%s: got %q %v %q", test.name, uri.mediaType, uri.params, uri.data)
+		}
+	}
+}
+
+func TestParseDataURIMalformed(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"empty", ""},
+		{"short", "abcde"},
+		{"scheme only", "data:"},
+		{"missing comma", "data:text/plain;base64"},
+		{"empty payload", "data:text/plain;base64,"},
+		{"empty plain payload", "data:,"},
+		{"only padding", "data:text/plain;base64,=="},
+		{"bad base64", "data:text/plain;base64,a"},
+		{"mixed alphabets", "data:text/plain;base64,+-8"},
+		{"bad characters", "data:text/plain;base64,aGV*bG8="},
+		{"bad media type", "data:text;base64,aGVsbG8="},
+		{"bad parameter", "data:text/plain;charset;base64,aGVsbG8="},
+		{"empty parameter name", "data:text/plain;=x;base64,aGVsbG8="},
+		{"bad percent escape", "data:text/plain,100%"},
+		{"bad parameter escape", "data:text/plain;name=%zz,hi"},
+		{"bare text", "not base64!"},
+	}
+	for _, test := range tests {
+		if uri, err := parseDataURI(test.value); err == nil {
+			t.Errorf("%s: got %+v", test.name, uri)
+		}
+	}
+}
+
+func TestFileRejectsEmptyPayload(t *testing.T) {
+	for _, rule := range []string{"f(file)", "f(image)"} {
+		for _, value := range []string{"", "data:image/png;base64,", "=="} {
+			_, err := ValidateJson(`{"f": "`+value+`"}`, []string{rule})
+			typ := "type." + rule[2:len(rule)-1]
+			if errs, ok := err.(ValidationErrors); !ok || errs[0].Rule != typ {
+				t.Errorf("%s with %q: got %v, want %s", rule, value, err, typ)
+			}
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package vgo
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateOffset matches one step of a relative date expression like the `-18y` of `now-18y`.
+var dateOffset = regexp.MustCompile(`^([+-]\d+)(mo|y|w|d|h|m|s)`)
+
+// resolveDate parses `now`, `today`, `yesterday` and `tomorrow`, optionally followed by offsets in years (y),
+// months (mo), weeks (w), days (d), hours (h), minutes (m) or seconds (s) like `today+30d` or `now-1y-6mo`,
+// and RFC3339 or `2006-01-02` dates. Days start at midnight in loc.
+func resolveDate(expr string, loc *time.Location) (time.Time, error) {
+	expr = strings.TrimSpace(expr)
+	base, offsets := expr, ""
+	if i := strings.IndexAny(expr, "+-"); i > 0 {
+		base, offsets = expr[:i], expr[i:]
+	}
+	now := time.Now().In(loc)
+	year, month, day := now.Date()
+	var tm time.Time
+	switch base {
+	case "now":
+		tm = now
+	case "today":
+		tm = time.Date(year, month, day, 0, 0, 0, 0, loc)
+	case "yesterday":
+		tm = time.Date(year, month, day-1, 0, 0, 0, 0, loc)
+	case "tomorrow":
+		tm = time.Date(year, month, day+1, 0, 0, 0, 0, loc)
+	default:
+		if parsed, err := time.Parse(time.RFC3339, expr); err == nil {
+			return parsed.In(loc), nil
+		}
+		return time.ParseInLocation("2006-01-02", expr, loc)
+	}
+	for offsets != "" {
+		match := dateOffset.FindStringSubmatch(offsets)
+		if match == nil {
+			return time.Time{}, fmt.Errorf("bad date offset %q", offsets)
+		}
+		n, err := strconv.Atoi(match[1])
+		if err != nil {
+			return time.Time{}, err
+		}
+		switch match[2] {
+		case "y":
+			tm = tm.AddDate(n, 0, 0)
+		case "mo":
+			tm = tm.AddDate(0, n, 0)
+		case "w":
+			tm = tm.AddDate(0, 0, 7*n)
+		case "d":
+			tm = tm.AddDate(0, 0, n)
+		case "h":
+			tm = tm.Add(time.Duration(n) * time.Hour)
+		case "m":
+			tm = tm.Add(time.Duration(n) * time.Minute)
+		case "s":
+			tm = tm.Add(time.Duration(n) * time.Second)
+		}
+		offsets = offsets[len(match[0]):]
+	}
+	return tm, nil
+}
+
+// resolveDateArg resolves an argument of the date rules like resolveDate. With jalali, set by the `jalali` option of
+// the field, dates like `1402/07/15` are read with the Jalali calendar so arguments mean the same day as values.
+func resolveDateArg(expr string, jalali bool, loc *time.Location) (time.Time, error) {
+	if jalali {
+		if tm, matched, err := parseJalali(expr, loc); matched {
+			return tm, err
+		}
+	}
+	return resolveDate(expr, loc)
+}
+
+// dateArg resolves an argument of the date rules, `field:name` refers to the value of another field.
+func (context *phaseContext) dateArg(obj subjectObj, arg string) (time.Time, bool) {
+	if strings.HasPrefix(arg, "field:") {
+		switch value := context.converted(obj, strings.TrimPrefix(arg, "field:")).(type) {
+		case time.Time:
+			return value, true
+		case string:
+			tm, err := resolveDate(value, context.timezone())
+			return tm, err == nil
+		}
+		return time.Time{}, false
+	}
+	tm, err := resolveDateArg(arg, context.hasTypeArg("jalali"), context.timezone())
+	return tm, err == nil
+}
+
+// parseDateValue converts the input of a date field, using the `layout=` option of the type when given.
+// With the `jalali` option dates like `1402/07/15` are read with the Jalali calendar.
+func (context *phaseContext) parseDateValue(value string) (time.Time, error) {
+	if context.hasTypeArg("jalali") {
+		if tm, matched, err := parseJalali(value, context.timezone()); matched {
+			return tm, err
+		}
+	}
+	layout := context.typeOption("layout")
+	if layout == "" {
+		return resolveDate(value, context.timezone())
+	}
+	return time.ParseInLocation(layout, value, context.timezone())
+}
+
+func (context *phaseContext) timezone() *time.Location {
+	if context.location == nil {
+		return time.UTC
+	}
+	return context.location
+}
+
+// dateArgRules are the date rules whose arguments are dates, they are checked once the options of the field are known.
+var dateArgRules = map[string]bool{
+	"after":   true,
+	"before":  true,
+	"between": true,
+}
+
+func checkDateArgs(args []string, jalali bool) error {
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "field:") {
+			if strings.TrimPrefix(arg, "field:") == "" {
+				return fmt.Errorf("missing field name in %q", arg)
+			}
+			continue
+		}
+		if _, err := resolveDateArg(arg, jalali, time.UTC); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkDateOptions(args []string) error {
+	for _, arg := range args {
+		if arg != "jalali" && (!strings.HasPrefix(arg, "layout=") || arg == "layout=") {
+			return fmt.Errorf("unknown date option %q", arg)
+		}
+	}
+	return nil
+}
+
+// formatDate renders a date of a message, in the Jalali calendar when the locale asks for it.
+func (context *phaseContext) formatDate(t time.Time) string {
+	if locale, ok := context.locale.(*Locale); ok && locale.Jalali {
+		return formatJalali(t)
+	}
+	return formatDate(t)
+}
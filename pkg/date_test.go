@@ -0,0 +1,151 @@
+package vgo
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDateValues(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  string
+		value string
+		want  time.Time
+		code  string
+	}{
+		{"gregorian", "d(date)", "2024-03-20", time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC), ""},
+		{"zero year stays gregorian", "d(date)", "0001-01-01", time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC), ""},
+		{"jalali year without option", "d(date)", "1402-07-15", time.Date(1402, 7, 15, 0, 0, 0, 0, time.UTC), ""},
+		{"jalali slashes without option", "d(date)", "1402/07/15", time.Time{}, "type.date"},
+		{"jalali option", "d(date(jalali))", "1402/07/15", time.Date(2023, 10, 7, 0, 0, 0, 0, time.UTC), ""},
+		{"jalali argument", "d(date(jalali)) after(1402/07/15)", "1402/07/16", time.Date(2023, 10, 8, 0, 0, 0, 0, time.UTC), ""},
+		{"jalali argument fails", "d(date(jalali)) after(1402/07/15)", "1402/07/14", time.Time{}, "date.after"},
+		{"jalali argument with dashes", "d(date(jalali)) before(1402-07-15)", "1402-07-14", time.Date(2023, 10, 6, 0, 0, 0, 0, time.UTC), ""},
+		{"gregorian argument", "d(date) after(0001-01-01)", "0001-01-02", time.Date(1, 1, 2, 0, 0, 0, 0, time.UTC), ""},
+		{"jalali year argument", "d(date) after(1402-07-15)", "1402-07-16", time.Date(1402, 7, 16, 0, 0, 0, 0, time.UTC), ""},
+		{"jalali year argument is gregorian", "d(date) before(1402-07-15)", "2023-10-08", time.Time{}, "date.before"},
+	}
+	for _, test := range tests {
+		values, err := ValidateJson(`{"d": "`+test.value+`"}`, []string{test.rule})
+		var code string
+		if errs, ok := err.(ValidationErrors); ok {
+			code = errs[0].Rule
+		} else if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if code != test.code {
+			t.Errorf("%s: got %q, want %q", test.name, code, test.code)
+			continue
+		}
+		if code == "" && !values["d"].(time.Time).Equal(test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, values["d"], test.want)
+		}
+	}
+}
+
+func TestDateArgumentsCompile(t *testing.T) {
+	tests := []struct {
+		rule string
+		ok   bool
+	}{
+		{"d(date) after(today-1y) before(2030-01-01)", true},
+		{"d(date) after(field:start)", true},
+		{"d(date) after(field:)", false},
+		{"d(date) after(1402/07/15)", false},
+		{"d(date(jalali)) after(1402/07/15) before(۱۴۰۵/۰۱/۰۱)", true},
+		{"d(date(jalali)) after(1402/12/30)", false},
+		{"d(date(jalali)) between(today,2030-01-01T00:00:00Z)", true},
+		{"d(date) after(tomorrow+1x)", false},
+	}
+	for _, test := range tests {
+		if _, err := Compile([]string{test.rule}); (err == nil) != test.ok {
+			t.Errorf("%s: got %v", test.rule, err)
+		}
+	}
+}
+
+func TestResolveDate(t *testing.T) {
+	tehran := time.FixedZone("Tehran", 3*3600+1800)
+	now := time.Now().In(tehran)
+	year, month, day := now.Date()
+	midnight := time.Date(year, month, day, 0, 0, 0, 0, tehran)
+	tests := []struct {
+		expr string
+		want time.Time
+		ok   bool
+	}{
+		{"today", midnight, true},
+		{" today+30d ", midnight.AddDate(0, 0, 30), true},
+		{"yesterday", midnight.AddDate(0, 0, -1), true},
+		{"tomorrow+2h", midnight.AddDate(0, 0, 1).Add(2 * time.Hour), true},
+		{"today-1y-6mo", midnight.AddDate(-1, -6, 0), true},
+		{"today+1w-3m+10s", midnight.AddDate(0, 0, 7).Add(-3*time.Minute + 10*time.Second), true},
+		{"2024-03-20", time.Date(2024, 3, 20, 0, 0, 0, 0, tehran), true},
+		{"2024-03-20T10:00:00Z", time.Date(2024, 3, 20, 10, 0, 0, 0, time.UTC), true},
+		{"today+1x", time.Time{}, false},
+		{"today+", time.Time{}, false},
+		{"today+d", time.Time{}, false},
+		{"today30d", time.Time{}, false},
+		{"later", time.Time{}, false},
+	}
+	for _, test := range tests {
+		got, err := resolveDate(test.expr, tehran)
+		if (err == nil) != test.ok {
+			t.Errorf("%q: got %v", test.expr, err)
+			continue
+		}
+		if test.ok && !got.Equal(test.want) {
+			t.Errorf("%q: got %v, want %v", test.expr, got, test.want)
+		}
+	}
+	got, err := resolveDate("now-18y", tehran)
+	if want := time.Now().AddDate(-18, 0, 0); err != nil || got.Sub(want) > time.Second || want.Sub(got) > time.Second {
+		t.Errorf("now-18y: got %v, %v", got, err)
+	}
+}
+
+func TestDateRules(t *testing.T) {
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1).Format("2006-01-02")
+	tests := []struct {
+		rules []string
+		body  string
+		errs  map[string]string
+	}{
+		{[]string{"d(date(layout=02.01.2006))"}, `{"d": "20.03.2024"}`, nil},
+		{[]string{"d(date(layout=02.01.2006))"}, `{"d": "2024-03-20"}`, map[string]string{"d": "type.date"}},
+		{[]string{"d(date(layout=2006-01-02)) before(now-18y)"}, `{"d": "2000-01-01"}`, nil},
+		{[]string{"d(date(layout=2006-01-02)) before(now-18y)"}, `{"d": "` + tomorrow + `"}`, map[string]string{"d": "date.before"}},
+		{[]string{"d(date) after(today) before(today+30d)"}, `{"d": "` + tomorrow + `T12:00:00Z"}`, nil},
+		{[]string{"d(date) after(today)"}, `{"d": "2000-01-01T00:00:00Z"}`, map[string]string{"d": "date.after"}},
+		{[]string{"start(date)", "end(date) after(field:start)"}, `{"start": "2024-03-20T00:00:00Z", "end": "2024-03-21T00:00:00Z"}`, nil},
+		{[]string{"start(date)", "end(date) after(field:start)"}, `{"start": "2024-03-20T00:00:00Z", "end": "2024-03-19T00:00:00Z"}`, map[string]string{"end": "date.after"}},
+		{[]string{"start(date(layout=2006-01-02))", "end(date) after(field:start)"}, `{"start": "2024-03-20", "end": "2024-03-20T12:00:00Z"}`, nil},
+		{[]string{"start(string)", "end(date) after(field:start)"}, `{"start": "2024-03-20", "end": "2024-03-19T00:00:00Z"}`, map[string]string{"end": "date.after"}},
+		{[]string{"end(date) after(field:start)"}, `{"end": "2024-03-19T00:00:00Z"}`, nil},
+	}
+	for _, test := range tests {
+		_, err := ValidateJson(test.body, test.rules)
+		if errs := failedRules(t, err); !reflect.DeepEqual(errs, test.errs) {
+			t.Errorf("%v with %s: got %v, want %v", test.rules, test.body, errs, test.errs)
+		}
+	}
+}
+
+func TestDateLocation(t *testing.T) {
+	tehran := time.FixedZone("Tehran", 3*3600+1800)
+	schema := MustCompile([]string{"d(date(layout=2006-01-02 15:04))", "r(date) after(2024-03-20)"}, WithLocation(tehran))
+	values, err := schema.ValidateJson(`{"d": "2024-03-20 08:00", "r": "2024-03-19T21:00:00Z"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2024, 3, 20, 4, 30, 0, 0, time.UTC); !values["d"].(time.Time).Equal(want) {
+		t.Errorf("d: got %v, want %v", values["d"], want)
+	}
+	if loc := values["r"].(time.Time).Location(); loc != tehran {
+		t.Errorf("r: got location %v", loc)
+	}
+	if _, err := schema.ValidateJson(`{"r": "2024-03-19T20:00:00Z"}`); failedRules(t, err)["r"] != "date.after" {
+		t.Errorf("r before Tehran midnight: got %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package vgo
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// ErrMalformedRequest is returned when the input can not be decoded.
+var ErrMalformedRequest = errors.New("malformed request")
+
+// FieldError describes a single failed rule.
+type FieldError struct {
+	// Field is the path of the field, e.g. `items.2.price`.
+	Field string `json:"field"`
+	// Rule is the machine readable code of the failed rule, e.g. `string.min`, `required` or `type.number`.
+	Rule string   `json:"rule"`
+	Args []string `json:"args,omitempty"`
+	// Value is the rejected input value, it is left empty for files and images.
+	Value   interface{} `json:"value,omitempty"`
+	Message string      `json:"message"`
+}
+
+// ValidationErrors is returned when the input does not satisfy the rules.
+type ValidationErrors []*FieldError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, item := range e {
+		messages[i] = item.Field + ": " + item.Message
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+// Field returns the errors reported for the given field path.
+func (e ValidationErrors) Field(name string) ValidationErrors {
+	var out ValidationErrors
+	for _, item := range e {
+		if item.Field == name {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// ProblemDetails is an RFC 7807 problem document.
+type ProblemDetails struct {
+	Type     string           `json:"type"`
+	Title    string           `json:"title"`
+	Status   int              `json:"status"`
+	Detail   string           `json:"detail,omitempty"`
+	Instance string           `json:"instance,omitempty"`
+	Errors   ValidationErrors `json:"errors,omitempty"`
+}
+
+// Problem wraps the errors in an RFC 7807 problem document with status 422.
+func (e ValidationErrors) Problem() *ProblemDetails {
+	return &ProblemDetails{
+		Type:   "about:blank",
+		Title:  http.StatusText(http.StatusUnprocessableEntity),
+		Status: http.StatusUnprocessableEntity,
+		Errors: e,
+	}
+}
@@ -0,0 +1,327 @@
+package vgo
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// File is the value of `file` and `image` fields.
+type File struct {
+	// MimeType is the type declared by the client, or the detected type when nothing specific was declared.
+	MimeType string
+	// DetectedType is the type sniffed from the first bytes of the content.
+	DetectedType string
+	// Buffer holds the content of files decoded from data URIs, uploaded files are read on demand by Bytes.
+	Buffer []byte
+	// Name is the client side file name of uploads.
+	Name string
+	Size int64
+	// Width and Height are the pixel dimensions of images.
+	Width  int
+	Height int
+	header *multipart.FileHeader
+}
+
+func uploadedFile(header *multipart.FileHeader) *File {
+	return &File{
+		MimeType: header.Header.Get("Content-Type"),
+		Name:     header.Filename,
+		Size:     header.Size,
+		header:   header,
+	}
+}
+
+// decodeFile converts an upload or a data URI into a *File.
+func decodeFile(context *phaseContext) bool {
+	if header, ok := context.value.(*multipart.FileHeader); ok {
+		context.value = uploadedFile(header)
+		return sniffFile(context)
+	}
+	val, ok := context.value.(string)
+	if !ok {
+		context.hasError = true
+		context.err = context.translate("type."+context.typ, context.attribute(context.name))
+		return false
+	}
+	uri, err := parseDataURI(val)
+	if err != nil {
+		context.hasError = true
+		context.err = context.translate("type."+context.typ, context.attribute(context.name))
+		return false
+	}
+	name := uri.params["name"]
+	if name == "" {
+		name = uri.params["filename"]
+	}
+	context.value = &File{
+		MimeType: uri.mediaType,
+		Buffer:   uri.data,
+		Name:     name,
+		Size:     int64(len(uri.data)),
+	}
+	return sniffFile(context)
+}
+
+// Open returns a reader over the content of the file, uploads are streamed from the multipart form.
+func (f *File) Open() (io.ReadCloser, error) {
+	if f.header != nil && f.Buffer == nil {
+		return f.header.Open()
+	}
+	return ioutil.NopCloser(bytes.NewReader(f.Buffer)), nil
+}
+
+// Bytes returns the content of the file, reading uploads into Buffer on first use.
+func (f *File) Bytes() ([]byte, error) {
+	if f.Buffer != nil || f.header == nil {
+		return f.Buffer, nil
+	}
+	reader, err := f.header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	f.Buffer = data
+	return data, nil
+}
+
+func (f *File) head() ([]byte, error) {
+	if f.Buffer != nil || f.header == nil {
+		if len(f.Buffer) > 512 {
+			return f.Buffer[:512], nil
+		}
+		return f.Buffer, nil
+	}
+	reader, err := f.header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	head := make([]byte, 512)
+	n, err := io.ReadFull(reader, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	return head[:n], nil
+}
+
+// Extension returns the lower case extension of the file name without the dot, or an extension of its type.
+func (f *File) Extension() string {
+	if exts := f.Extensions(); len(exts) > 0 {
+		return exts[0]
+	}
+	return ""
+}
+
+// Extensions returns the lower case extension of the file name without the dot. A file without a name gets every
+// extension the system knows for its sniffed content type, their order depends on the host.
+func (f *File) Extensions() []string {
+	if ext := filepath.Ext(f.Name); ext != "" {
+		return []string{strings.ToLower(ext[1:])}
+	}
+	typ := f.contentType()
+	if alias, ok := mimeAliases[typ]; ok {
+		typ = alias
+	}
+	exts, _ := mime.ExtensionsByType(typ)
+	out := make([]string, 0, len(exts))
+	for _, ext := range exts {
+		out = append(out, strings.ToLower(ext[1:]))
+	}
+	return out
+}
+
+func mediaType(value string) string {
+	typ, _, err := mime.ParseMediaType(value)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(value))
+	}
+	return typ
+}
+
+var mimeAliases = map[string]string{
+	"image/jpg":                    "image/jpeg",
+	"image/pjpeg":                  "image/jpeg",
+	"image/x-png":                  "image/png",
+	"application/x-zip-compressed": "application/zip",
+	"audio/mp3":                    "audio/mpeg",
+}
+
+// compatibleTypes reports whether a declared type agrees with the type sniffed from the content. Sniffing only
+// recognizes containers for some formats, e.g. docx is detected as zip and json as plain text.
+func compatibleTypes(claimed, detected string) bool {
+	if alias, ok := mimeAliases[claimed]; ok {
+		claimed = alias
+	}
+	if claimed == detected {
+		return true
+	}
+	switch detected {
+	case "application/octet-stream":
+		return !hasSignature(claimed)
+	case "application/zip":
+		return zipFamily(claimed)
+	case "text/plain", "text/xml", "text/html":
+		return strings.HasPrefix(claimed, "text/") || strings.HasSuffix(claimed, "+xml") ||
+			strings.HasSuffix(claimed, "+json") || claimed == "application/json" || claimed == "application/xml" ||
+			claimed == "application/javascript" || claimed == "application/x-yaml" || claimed == "application/yaml"
+	}
+	return false
+}
+
+// zipFamily reports whether a type is stored in a zip container.
+func zipFamily(typ string) bool {
+	return typ == "application/zip" || strings.Contains(typ, "openxmlformats") || strings.Contains(typ, "opendocument") ||
+		strings.HasSuffix(typ, "+zip") || typ == "application/java-archive" ||
+		typ == "application/vnd.android.package-archive"
+}
+
+// hasSignature reports whether content of type typ starts with magic bytes, so that content which can not be
+// sniffed as anything does not match it.
+func hasSignature(typ string) bool {
+	return (strings.HasPrefix(typ, "image/") && typ != "image/svg+xml") || typ == "application/pdf" || zipFamily(typ)
+}
+
+// contentType is the type the mimes rule checks: the sniffed type when it identifies the format, otherwise the
+// declared type, which sniffFile found to agree with the content.
+func (f *File) contentType() string {
+	switch f.DetectedType {
+	case "", "application/octet-stream", "application/zip", "text/plain", "text/xml", "text/html":
+		return mediaType(f.MimeType)
+	}
+	return f.DetectedType
+}
+
+// sniffFile detects the content type of the converted file and rejects a declared type that does not match it.
+func sniffFile(context *phaseContext) bool {
+	file := context.value.(*File)
+	head, err := file.head()
+	if err != nil {
+		context.hasError = true
+		context.err = context.translate("type."+context.typ, context.attribute(context.name))
+		return false
+	}
+	file.DetectedType = mediaType(http.DetectContentType(head))
+	claimed := mediaType(file.MimeType)
+	if claimed == "" || claimed == "application/octet-stream" {
+		file.MimeType = file.DetectedType
+		return true
+	}
+	if !compatibleTypes(claimed, file.DetectedType) {
+		context.hasError = true
+		context.code = context.typ + ".mimeMismatch"
+		context.err = context.translate("file.mimeMismatch", context.attribute(context.name))
+		return false
+	}
+	return true
+}
+
+var sizeUnits = map[string]int64{
+	"":   1,
+	"B":  1,
+	"K":  1 << 10,
+	"KB": 1 << 10,
+	"M":  1 << 20,
+	"MB": 1 << 20,
+	"G":  1 << 30,
+	"GB": 1 << 30,
+}
+
+// parseSize parses sizes like `512`, `1KB` or `2.5MB`, units are powers of 1024.
+func parseSize(value string) (int64, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	i := strings.IndexFunc(value, func(char rune) bool {
+		return (char < '0' || char > '9') && char != '.'
+	})
+	if i < 0 {
+		i = len(value)
+	}
+	number, err := strconv.ParseFloat(value[:i], 64)
+	unit, ok := sizeUnits[strings.TrimSpace(value[i:])]
+	if err != nil || !ok || number < 0 {
+		return 0, fmt.Errorf("malformed size %q", value)
+	}
+	return int64(number * float64(unit)), nil
+}
+
+func prepareSize(args []string) (interface{}, error) {
+	return parseSize(args[0])
+}
+
+func matchMime(typ string, patterns []string) bool {
+	for _, pattern := range patterns {
+		pattern = mediaType(pattern)
+		if alias, ok := mimeAliases[pattern]; ok {
+			pattern = alias
+		}
+		if pattern == typ || (strings.HasSuffix(pattern, "/*") && strings.HasPrefix(typ, pattern[:len(pattern)-1])) {
+			return true
+		}
+	}
+	return false
+}
+
+func fileValidators() map[string]validatorFunc {
+	return map[string]validatorFunc{
+		"maxSize": func(context *phaseContext, obj subjectObj) error {
+			if context.value == nil {
+				return nil
+			}
+			if context.value.(*File).Size > context.prepared.(int64) {
+				context.hasError = true
+				context.err = context.translate("file.maxSize", context.attribute(context.name), context.args[0])
+			}
+			return nil
+		},
+		"minSize": func(context *phaseContext, obj subjectObj) error {
+			if context.value == nil {
+				return nil
+			}
+			if context.value.(*File).Size < context.prepared.(int64) {
+				context.hasError = true
+				context.err = context.translate("file.minSize", context.attribute(context.name), context.args[0])
+			}
+			return nil
+		},
+		"mimes": func(context *phaseContext, obj subjectObj) error {
+			if context.value == nil {
+				return nil
+			}
+			typ := context.value.(*File).contentType()
+			if alias, ok := mimeAliases[typ]; ok {
+				typ = alias
+			}
+			if !matchMime(typ, context.args) {
+				context.hasError = true
+				context.err = context.translate("file.mimes", context.attribute(context.name), strings.Join(context.args, ","))
+			}
+			return nil
+		},
+		"extensions": func(context *phaseContext, obj subjectObj) error {
+			if context.value == nil {
+				return nil
+			}
+			for _, ext := range context.value.(*File).Extensions() {
+				for _, arg := range context.args {
+					if strings.EqualFold(strings.TrimPrefix(arg, "."), ext) {
+						return nil
+					}
+				}
+			}
+			context.hasError = true
+			context.err = context.translate("file.extensions", context.attribute(context.name), strings.Join(context.args, ","))
+			return nil
+		},
+	}
+}
@@ -0,0 +1,94 @@
+package vgo
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func encodeDataURI(typ string, content []byte) string {
+	return "data:" + typ + ";base64," + base64.StdEncoding.EncodeToString(content)
+}
+
+var (
+	pngHead  = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	jpegHead = []byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00")
+	zipHead  = []byte("PK\x03\x04\x14\x00\x00\x00")
+	exeHead  = []byte("MZ\x90\x00\x03\x00\x00\x00\x04\x00\x00\x00\xff\xff")
+)
+
+func TestFileSniffing(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  string
+		value string
+		code  string
+	}{
+		{"matching png", "f(file) mimes(image/png)", encodeDataURI("image/png", pngHead), ""},
+		{"exe declared as png", "f(file) mimes(image/png)", encodeDataURI("image/png", exeHead), "file.mimeMismatch"},
+		{"exe declared as pdf", "f(file)", encodeDataURI("application/pdf", exeHead), "file.mimeMismatch"},
+		{"exe declared as docx", "f(file)", encodeDataURI("application/vnd.openxmlformats-officedocument.wordprocessingml.document", exeHead), "file.mimeMismatch"},
+		{"jpeg declared as png", "f(file)", encodeDataURI("image/png", jpegHead), "file.mimeMismatch"},
+		{"undeclared jpeg", "f(file) mimes(image/png)", encodeDataURI("application/octet-stream", jpegHead), "file.mimes"},
+		{"undeclared png", "f(file) mimes(image/png)", encodeDataURI("application/octet-stream", pngHead), ""},
+		{"docx in zip", "f(file) mimes(application/vnd.openxmlformats-officedocument.wordprocessingml.document)",
+			encodeDataURI("application/vnd.openxmlformats-officedocument.wordprocessingml.document", zipHead), ""},
+		{"csv as text", "f(file) mimes(text/csv)", encodeDataURI("text/csv", []byte("a,b\n1,2\n")), ""},
+		{"binary without signature", "f(file) mimes(application/x-custom)", encodeDataURI("application/x-custom", exeHead), ""},
+		{"alias", "f(file) mimes(image/jpeg)", encodeDataURI("image/jpg", jpegHead), ""},
+		{"wildcard", "f(file) mimes(image/*)", encodeDataURI("image/png", pngHead), ""},
+		{"unnamed jpeg as jpg", "f(file) extensions(jpg)", encodeDataURI("image/jpeg", jpegHead), ""},
+		{"unnamed jpeg as jpeg", "f(file) extensions(.jpeg,png)", encodeDataURI("image/jpg", jpegHead), ""},
+		{"undeclared jpeg as jpg", "f(file) extensions(jpg)", encodeDataURI("application/octet-stream", jpegHead), ""},
+		{"unnamed jpeg as png", "f(file) extensions(png)", encodeDataURI("image/jpeg", jpegHead), "file.extensions"},
+	}
+	for _, test := range tests {
+		_, err := ValidateJson(`{"f": "`+test.value+`"}`, []string{test.rule})
+		var code string
+		if errs, ok := err.(ValidationErrors); ok {
+			code = errs[0].Rule
+		} else if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if code != test.code {
+			t.Errorf("%s: got %q, want %q", test.name, code, test.code)
+		}
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int64
+		ok    bool
+	}{
+		{"512", 512, true},
+		{"100KB", 100 << 10, true},
+		{"2MB", 2 << 20, true},
+		{"1.5k", 1536, true},
+		{"x", 0, false},
+	}
+	for _, test := range tests {
+		got, err := parseSize(test.value)
+		if (err == nil) != test.ok || (test.ok && got != test.want) {
+			t.Errorf("parseSize(%q) = %d, %v", test.value, got, err)
+		}
+	}
+}
+
+func TestFileExtensions(t *testing.T) {
+	named := &File{Name: "photo.JPEG", MimeType: "image/png"}
+	if got := named.Extensions(); len(got) != 1 || got[0] != "jpeg" || named.Extension() != "jpeg" {
+		t.Errorf("named: got %v", got)
+	}
+	unnamed := &File{MimeType: "image/jpg", DetectedType: "image/jpeg"}
+	var jpg bool
+	for _, ext := range unnamed.Extensions() {
+		jpg = jpg || ext == "jpg"
+	}
+	if !jpg {
+		t.Errorf("unnamed: got %v", unnamed.Extensions())
+	}
+	if got := (&File{}).Extension(); got != "" {
+		t.Errorf("empty: got %q", got)
+	}
+}
@@ -0,0 +1,233 @@
+package vgo
+
+import (
+	"fmt"
+	"mime/multipart"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var truthy = map[string]bool{"1": true, "true": true, "on": true, "yes": true}
+var falsy = map[string]bool{"0": true, "false": true, "off": true, "no": true}
+
+func coerceBool(value string) (bool, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if truthy[value] {
+		return true, true
+	}
+	if falsy[value] {
+		return false, true
+	}
+	return false, false
+}
+
+// truthValue maps booleans, the strings accepted by coerceBool and the numbers 1 and 0 to a bool.
+func truthValue(value interface{}) (bool, bool) {
+	switch val := value.(type) {
+	case bool:
+		return val, true
+	case string:
+		return coerceBool(val)
+	}
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return coerceBool(fmt.Sprint(value))
+	}
+	return false, false
+}
+
+// ValidateValues validates query string or form values. Keys may use dot paths (`address.city`) or brackets
+// (`address[city]`, `tags[]`, `items[0][price]`), repeated keys become arrays and strings are coerced to the field types.
+func ValidateValues(values url.Values, rules []string, opts ...Option) (map[string]interface{}, error) {
+	schema, messages, err := schemaFor(rules, opts)
+	if err != nil {
+		return messages, err
+	}
+	return schema.ValidateValues(values, opts...)
+}
+
+// ValidateValues validates query string or form values, see the package level ValidateValues.
+func (s *Schema) ValidateValues(values url.Values, opts ...Option) (map[string]interface{}, error) {
+	data := decodeValues(values)
+	s.coerce(data)
+	return s.validateMap(data, opts)
+}
+
+// ValidateMultipart validates a parsed multipart form, text parts are handled like ValidateValues and the uploads of
+// `file` and `image` fields become *File values that read the upload on demand.
+func ValidateMultipart(form *multipart.Form, rules []string, opts ...Option) (map[string]interface{}, error) {
+	schema, messages, err := schemaFor(rules, opts)
+	if err != nil {
+		return messages, err
+	}
+	return schema.ValidateMultipart(form, opts...)
+}
+
+// ValidateMultipart validates a parsed multipart form, see the package level ValidateMultipart.
+func (s *Schema) ValidateMultipart(form *multipart.Form, opts ...Option) (map[string]interface{}, error) {
+	data := decodeForm(form.Value, form.File)
+	s.coerce(data)
+	return s.validateMap(data, opts)
+}
+
+func decodeValues(values url.Values) map[string]interface{} {
+	return decodeForm(values, nil)
+}
+
+func decodeForm(values url.Values, files map[string][]*multipart.FileHeader) map[string]interface{} {
+	data := make(map[string]interface{})
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		items := make([]interface{}, len(values[key]))
+		for i, item := range values[key] {
+			items[i] = item
+		}
+		setFormValue(data, key, items)
+	}
+	keys = keys[:0]
+	for key := range files {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		items := make([]interface{}, len(files[key]))
+		for i, item := range files[key] {
+			items[i] = item
+		}
+		setFormValue(data, key, items)
+	}
+	for key, value := range data {
+		data[key] = listify(value)
+	}
+	return data
+}
+
+func setFormValue(data map[string]interface{}, key string, items []interface{}) {
+	path, list := parseFormKey(key)
+	if len(path) == 0 || len(items) == 0 {
+		return
+	}
+	var value interface{} = items[0]
+	if list || len(items) > 1 {
+		value = items
+	}
+	current := data
+	for _, segment := range path[:len(path)-1] {
+		next, ok := current[segment].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			current[segment] = next
+		}
+		current = next
+	}
+	current[path[len(path)-1]] = value
+}
+
+// parseFormKey splits `items[0][price]` or `items.0.price` into segments, list reports a trailing `[]`.
+func parseFormKey(key string) ([]string, bool) {
+	list := false
+	if strings.HasSuffix(key, "[]") {
+		list = true
+		key = key[:len(key)-2]
+	}
+	key = strings.ReplaceAll(key, "]", "")
+	key = strings.ReplaceAll(key, "[", ".")
+	var path []string
+	for _, segment := range strings.Split(key, ".") {
+		if segment != "" {
+			path = append(path, segment)
+		}
+	}
+	return path, list
+}
+
+// maxFormIndex bounds the indexes of form keys like `items[3]`, objects with larger indexes are kept as objects so
+// a single key can not allocate a huge array.
+const maxFormIndex = 10000
+
+// listify turns objects whose keys are all indexes into arrays. Every element keeps its index, missing indexes are
+// left as nil so errors point at the row the client sent.
+func listify(value interface{}) interface{} {
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return value
+	}
+	last := -1
+	for key, item := range obj {
+		obj[key] = listify(item)
+		index, err := strconv.Atoi(key)
+		if err != nil || index < 0 || index >= maxFormIndex || strconv.Itoa(index) != key {
+			ok = false
+		} else if index > last {
+			last = index
+		}
+	}
+	if !ok || len(obj) == 0 {
+		return obj
+	}
+	array := make([]interface{}, last+1)
+	for key, item := range obj {
+		index, _ := strconv.Atoi(key)
+		array[index] = item
+	}
+	return array
+}
+
+// coerce converts the strings decoded from form values into the shapes the field types expect.
+func (s *Schema) coerce(data map[string]interface{}) {
+	for _, field := range s.fields {
+		for i, key := range field.path {
+			if key != "*" {
+				continue
+			}
+			for _, path := range expandPath(data, field.path[:i]) {
+				if value, ok := lookupPath(data, path); ok {
+					if _, ok := value.([]interface{}); !ok && value != nil {
+						setPath(data, data, path, []interface{}{value})
+					}
+				}
+			}
+		}
+		for _, path := range expandPath(data, field.path) {
+			value, ok := lookupPath(data, path)
+			if !ok {
+				continue
+			}
+			setPath(data, data, path, coerceValue(field.typ, value))
+		}
+	}
+}
+
+func coerceValue(typ string, value interface{}) interface{} {
+	if typ == "array" {
+		if _, ok := value.([]interface{}); !ok && value != nil {
+			return []interface{}{value}
+		}
+		return value
+	}
+	if items, ok := value.([]interface{}); ok && typ != "any" && typ != "object" && len(items) > 0 {
+		value = items[0]
+	}
+	str, ok := value.(string)
+	if !ok || typ == "string" || typ == "any" {
+		return value
+	}
+	if str == "" {
+		return nil
+	}
+	if typ == "bool" {
+		if b, ok := coerceBool(str); ok {
+			return b
+		}
+	}
+	return value
+}
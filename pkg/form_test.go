@@ -0,0 +1,107 @@
+package vgo
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		values url.Values
+		want   map[string]interface{}
+	}{
+		{"plain", url.Values{"a": {"1"}}, map[string]interface{}{"a": "1"}},
+		{"repeated", url.Values{"a": {"1", "2"}}, map[string]interface{}{"a": []interface{}{"1", "2"}}},
+		{"brackets", url.Values{"tags[]": {"x"}}, map[string]interface{}{"tags": []interface{}{"x"}}},
+		{"nested", url.Values{"address[city]": {"T"}}, map[string]interface{}{"address": map[string]interface{}{"city": "T"}}},
+		{"indexes", url.Values{"items[1][sku]": {"b"}, "items[0][sku]": {"a"}}, map[string]interface{}{
+			"items": []interface{}{map[string]interface{}{"sku": "a"}, map[string]interface{}{"sku": "b"}},
+		}},
+		{"numeric root keys", url.Values{"0": {"a"}, "1": {"b"}}, map[string]interface{}{"0": "a", "1": "b"}},
+		{"sparse indexes", url.Values{"items[0]": {"a"}, "items[2]": {"c"}}, map[string]interface{}{
+			"items": []interface{}{"a", nil, "c"},
+		}},
+		{"padded index", url.Values{"items[01]": {"a"}}, map[string]interface{}{
+			"items": map[string]interface{}{"01": "a"},
+		}},
+		{"huge index", url.Values{"items[99999999]": {"a"}}, map[string]interface{}{
+			"items": map[string]interface{}{"99999999": "a"},
+		}},
+	}
+	for _, test := range tests {
+		if got := decodeValues(test.values); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestValidateValuesNumericKeys(t *testing.T) {
+	values, err := ValidateValues(url.Values{"0": {"x"}}, []string{"page(number)"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values["page"] != nil {
+		t.Errorf("got %v", values)
+	}
+}
+
+func TestCoerceValue(t *testing.T) {
+	tests := []struct {
+		typ   string
+		value interface{}
+		want  interface{}
+	}{
+		{"bool", "on", true},
+		{"bool", "0", false},
+		{"number", "", nil},
+		{"string", "", ""},
+		{"array", "a", []interface{}{"a"}},
+		{"string", []interface{}{"a", "b"}, "a"},
+	}
+	for _, test := range tests {
+		if got := coerceValue(test.typ, test.value); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("coerceValue(%q, %v) = %v, want %v", test.typ, test.value, got, test.want)
+		}
+	}
+}
+
+func TestTruthValue(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  bool
+		ok    bool
+	}{
+		{true, true, true},
+		{false, false, true},
+		{"true", true, true},
+		{" Yes ", true, true},
+		{"ON", true, true},
+		{"off", false, true},
+		{"0", false, true},
+		{"", false, false},
+		{"maybe", false, false},
+		{1.0, true, true},
+		{int64(0), false, true},
+		{uint64(1), true, true},
+		{2.0, false, false},
+		{0.5, false, false},
+		{nil, false, false},
+		{[]interface{}{true}, false, false},
+	}
+	for _, test := range tests {
+		if got, ok := truthValue(test.value); got != test.want || ok != test.ok {
+			t.Errorf("truthValue(%#v) = %v, %v, want %v, %v", test.value, got, ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestValidateValuesSparseIndexes(t *testing.T) {
+	values := url.Values{"items[0][p]": {"3"}, "items[2][p]": {"x"}}
+	_, err := ValidateValues(values, []string{"items(array)", "items.*.p(number)"})
+	errs, ok := err.(ValidationErrors)
+	if !ok || len(errs) != 1 || errs[0].Field != "items.2.p" {
+		t.Errorf("got %v", err)
+	}
+}
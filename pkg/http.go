@@ -0,0 +1,188 @@
+package vgo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+)
+
+// ErrBodyTooLarge is returned when the request body exceeds the configured limit.
+var ErrBodyTooLarge = errors.New("request body too large")
+
+// DefaultMaxBodySize is the body limit used when WithMaxBodySize is not given.
+const DefaultMaxBodySize int64 = 1 << 20
+
+// DefaultMaxMultipartSize is the body limit of multipart requests used when WithMaxBodySize is not given.
+const DefaultMaxMultipartSize int64 = 32 << 20
+
+// multipartMemory is the part of a multipart body kept in memory, larger uploads are spooled to temporary files.
+const multipartMemory = 8 << 20
+
+// ErrorWriter writes the response of a request that could not be bound.
+type ErrorWriter func(w http.ResponseWriter, r *http.Request, err error)
+
+type valuesKey struct{}
+
+// WithMaxBodySize limits the number of bytes read from a request body.
+func WithMaxBodySize(size int64) Option {
+	return func(o *options) {
+		o.maxBodySize = size
+	}
+}
+
+// WithErrorWriter replaces the problem details response written by Middleware.
+func WithErrorWriter(writer ErrorWriter) Option {
+	return func(o *options) {
+		o.errorWriter = writer
+	}
+}
+
+// Bind validates the query string of GET and HEAD requests and the JSON, form encoded or multipart body of other requests.
+// Messages are rendered in the language of the Accept-Language header unless the schema or opts set a locale.
+// The error is ErrBodyTooLarge, ErrMalformedRequest or ValidationErrors.
+func Bind(r *http.Request, schema *Schema, opts ...Option) (map[string]interface{}, error) {
+	o := schema.options.with(opts)
+	if o.locale == nil {
+		opts = append(opts[:len(opts):len(opts)], WithLanguage(r.Header.Get("Accept-Language")))
+		o = schema.options.with(opts)
+	}
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return schema.ValidateValues(r.URL.Query(), opts...)
+	}
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if contentType == "multipart/form-data" {
+		form, err := readMultipart(r, o.maxBodySize)
+		if err != nil {
+			return nil, err
+		}
+		return schema.ValidateMultipart(form, opts...)
+	}
+	body, err := readBody(r, o.maxBodySize)
+	if err != nil {
+		return nil, err
+	}
+	if contentType == "application/x-www-form-urlencoded" {
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return nil, ErrMalformedRequest
+		}
+		return schema.ValidateValues(values, opts...)
+	}
+	data, err := schema.decodeJSON(body)
+	if err != nil {
+		return nil, ErrMalformedRequest
+	}
+	return schema.Check(data, opts...)
+}
+
+// Middleware binds every request with schema, on success the values are available through Values.
+// Failures are answered by the error writer, which by default writes RFC 7807 problem details.
+func Middleware(schema *Schema, opts ...Option) func(http.Handler) http.Handler {
+	writer := schema.options.with(opts).errorWriter
+	if writer == nil {
+		writer = WriteProblem
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			values, err := Bind(r, schema, opts...)
+			if err != nil {
+				writer(w, r, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), valuesKey{}, values)))
+		})
+	}
+}
+
+// Values returns the values stored by Middleware.
+func Values(ctx context.Context) map[string]interface{} {
+	values, _ := ctx.Value(valuesKey{}).(map[string]interface{})
+	return values
+}
+
+// StatusOf maps an error returned by Bind to an http status code.
+func StatusOf(err error) int {
+	switch err.(type) {
+	case ValidationErrors:
+		return http.StatusUnprocessableEntity
+	}
+	switch err {
+	case ErrMalformedRequest:
+		return http.StatusBadRequest
+	case ErrBodyTooLarge:
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusInternalServerError
+}
+
+// WriteProblem is the default ErrorWriter, it answers with an `application/problem+json` document.
+func WriteProblem(w http.ResponseWriter, r *http.Request, err error) {
+	status := StatusOf(err)
+	problem := &ProblemDetails{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+	}
+	if errs, ok := err.(ValidationErrors); ok {
+		problem = errs.Problem()
+	} else if status != http.StatusInternalServerError {
+		problem.Detail = err.Error()
+	}
+	problem.Instance = r.URL.Path
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(problem)
+}
+
+func readBody(r *http.Request, limit int64) ([]byte, error) {
+	if r.Body == nil {
+		return nil, ErrMalformedRequest
+	}
+	if limit <= 0 {
+		limit = DefaultMaxBodySize
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, limit+1))
+	if err != nil {
+		return nil, ErrMalformedRequest
+	}
+	if int64(len(body)) > limit {
+		return nil, ErrBodyTooLarge
+	}
+	return body, nil
+}
+
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.reader.Read(p)
+	c.count += int64(n)
+	return n, err
+}
+
+func readMultipart(r *http.Request, limit int64) (*multipart.Form, error) {
+	if r.Body == nil {
+		return nil, ErrMalformedRequest
+	}
+	if limit <= 0 {
+		limit = DefaultMaxMultipartSize
+	}
+	counter := &countingReader{reader: io.LimitReader(r.Body, limit+1)}
+	r.Body = ioutil.NopCloser(counter)
+	err := r.ParseMultipartForm(multipartMemory)
+	if counter.count > limit {
+		return nil, ErrBodyTooLarge
+	}
+	if err != nil {
+		return nil, ErrMalformedRequest
+	}
+	return r.MultipartForm, nil
+}
@@ -0,0 +1,144 @@
+package vgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func multipartBody(t *testing.T, fields map[string]string, file []byte) (*bytes.Buffer, string) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for key, value := range fields {
+		if err := writer.WriteField(key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if file != nil {
+		part, err := writer.CreateFormFile("avatar", "avatar.png")
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write(file)
+	}
+	writer.Close()
+	return body, writer.FormDataContentType()
+}
+
+func TestMiddleware(t *testing.T) {
+	schema := MustCompile([]string{"name(string) required min(3)", "age(int)", "avatar(file)"})
+	handler := Middleware(schema, WithMaxBodySize(1<<10))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(Values(r.Context()))
+	}))
+	form, formType := multipartBody(t, map[string]string{"name": "Ali", "age": "30"}, pngHead)
+	badForm, badFormType := multipartBody(t, map[string]string{"name": "A"}, nil)
+	large, largeType := multipartBody(t, map[string]string{"name": strings.Repeat("a", 2<<10)}, nil)
+	tests := []struct {
+		name        string
+		method      string
+		target      string
+		contentType string
+		body        string
+		status      int
+		contains    string
+	}{
+		{"json", http.MethodPost, "/", "application/json", `{"name": "Ali", "age": 30}`, http.StatusOK, `"age":30`},
+		{"query", http.MethodGet, "/?name=Ali&age=7", "", "", http.StatusOK, `"name":"Ali"`},
+		{"form", http.MethodPost, "/", "application/x-www-form-urlencoded", "name=Ali&age=5", http.StatusOK, `"age":5`},
+		{"multipart", http.MethodPost, "/", formType, form.String(), http.StatusOK, `"name":"Ali"`},
+		{"malformed json", http.MethodPost, "/", "application/json", `{"name":`, http.StatusBadRequest, ErrMalformedRequest.Error()},
+		{"trailing json", http.MethodPost, "/", "application/json", `{"name": "Ali"} {}`, http.StatusBadRequest, ErrMalformedRequest.Error()},
+		{"too large", http.MethodPost, "/", "application/json", `{"name": "` + strings.Repeat("a", 2<<10) + `"}`,
+			http.StatusRequestEntityTooLarge, ErrBodyTooLarge.Error()},
+		{"too large multipart", http.MethodPost, "/", largeType, large.String(), http.StatusRequestEntityTooLarge, ErrBodyTooLarge.Error()},
+		{"invalid json", http.MethodPost, "/", "application/json", `{"name": "A"}`, http.StatusUnprocessableEntity, `"field":"name"`},
+		{"invalid age", http.MethodPost, "/", "application/json", `{"name": "Ali", "age": 1.5}`, http.StatusUnprocessableEntity, `"rule":"type.int"`},
+		{"invalid multipart", http.MethodPost, "/", badFormType, badForm.String(), http.StatusUnprocessableEntity, `"rule":"string.min"`},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest(test.method, test.target, strings.NewReader(test.body))
+		if test.contentType != "" {
+			r.Header.Set("Content-Type", test.contentType)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != test.status || !strings.Contains(w.Body.String(), test.contains) {
+			t.Errorf("%s: got %d %s", test.name, w.Code, w.Body.String())
+		}
+		if test.status != http.StatusOK && w.Header().Get("Content-Type") != "application/problem+json" {
+			t.Errorf("%s: got content type %q", test.name, w.Header().Get("Content-Type"))
+		}
+	}
+}
+
+func TestWriteProblem(t *testing.T) {
+	_, err := ValidateJson(`{"name": "A"}`, []string{"name(string) min(3)"}, WithLocale(English))
+	r := httptest.NewRequest(http.MethodPost, "/users", nil)
+	w := httptest.NewRecorder()
+	WriteProblem(w, r, err)
+	var problem ProblemDetails
+	if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusUnprocessableEntity || problem.Status != w.Code || problem.Instance != "/users" ||
+		len(problem.Errors) != 1 || problem.Errors[0].Rule != "string.min" {
+		t.Errorf("got %d %+v", w.Code, problem)
+	}
+	w = httptest.NewRecorder()
+	WriteProblem(w, r, ErrBodyTooLarge)
+	if w.Code != http.StatusRequestEntityTooLarge || !strings.Contains(w.Body.String(), `"detail":"request body too large"`) {
+		t.Errorf("got %d %s", w.Code, w.Body.String())
+	}
+	w = httptest.NewRecorder()
+	WriteProblem(w, r, bytes.ErrTooLarge)
+	if w.Code != http.StatusInternalServerError || strings.Contains(w.Body.String(), "detail") {
+		t.Errorf("got %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestBindLanguage(t *testing.T) {
+	rules := []string{"name(string) required"}
+	tests := []struct {
+		name   string
+		schema *Schema
+		opts   []Option
+		header string
+		want   string
+	}{
+		{"header", MustCompile(rules), nil, "en-US,en;q=0.9", English.Messages["required"]},
+		{"default", MustCompile(rules), nil, "", Persian.Messages["required"]},
+		{"schema locale", MustCompile(rules, WithLocale(English)), nil, "fa", English.Messages["required"]},
+		{"call locale", MustCompile(rules), []Option{WithLocale(English)}, "fa", English.Messages["required"]},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+		r.Header.Set("Accept-Language", test.header)
+		_, err := Bind(r, test.schema, test.opts...)
+		errs, ok := err.(ValidationErrors)
+		if !ok {
+			t.Fatalf("%s: got %v", test.name, err)
+		}
+		prefix := strings.SplitN(test.want, "%s", 2)[0]
+		if !strings.HasPrefix(errs[0].Message, prefix) {
+			t.Errorf("%s: got %q", test.name, errs[0].Message)
+		}
+	}
+}
+
+func TestMiddlewareErrorWriter(t *testing.T) {
+	schema := MustCompile([]string{"name(string) required"})
+	var got error
+	handler := Middleware(schema, WithErrorWriter(func(w http.ResponseWriter, r *http.Request, err error) {
+		got = err
+		w.WriteHeader(http.StatusTeapot)
+	}))(http.NotFoundHandler())
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`)))
+	if _, ok := got.(ValidationErrors); !ok || w.Code != http.StatusTeapot {
+		t.Errorf("got %d %v", w.Code, got)
+	}
+}
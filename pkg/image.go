@@ -0,0 +1,160 @@
+package vgo
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// decodeImage reads the dimensions of the converted image from its header.
+func decodeImage(context *phaseContext) bool {
+	file := context.value.(*File)
+	reader, err := file.Open()
+	if err == nil {
+		defer reader.Close()
+		var config image.Config
+		config, err = decodeImageConfig(reader)
+		file.Width = config.Width
+		file.Height = config.Height
+	}
+	if err != nil {
+		context.hasError = true
+		context.err = context.translate("type.image", context.attribute(context.name))
+		return false
+	}
+	return true
+}
+
+func decodeImageConfig(reader io.Reader) (image.Config, error) {
+	buffered := bufio.NewReader(reader)
+	head, _ := buffered.Peek(30)
+	if width, height, ok := webpSize(head); ok {
+		return image.Config{Width: width, Height: height}, nil
+	}
+	config, _, err := image.DecodeConfig(buffered)
+	return config, err
+}
+
+// webpSize reads the canvas size from the first chunk of a WebP file.
+func webpSize(head []byte) (int, int, bool) {
+	if len(head) < 30 || string(head[0:4]) != "RIFF" || string(head[8:12]) != "WEBP" {
+		return 0, 0, false
+	}
+	switch string(head[12:16]) {
+	case "VP8 ":
+		if head[23] != 0x9d || head[24] != 0x01 || head[25] != 0x2a {
+			return 0, 0, false
+		}
+		width := int(binary.LittleEndian.Uint16(head[26:28]) & 0x3fff)
+		height := int(binary.LittleEndian.Uint16(head[28:30]) & 0x3fff)
+		return width, height, true
+	case "VP8L":
+		if head[20] != 0x2f {
+			return 0, 0, false
+		}
+		bits := binary.LittleEndian.Uint32(head[21:25])
+		return int(bits&0x3fff) + 1, int(bits>>14&0x3fff) + 1, true
+	case "VP8X":
+		width := int(head[24]) | int(head[25])<<8 | int(head[26])<<16
+		height := int(head[27]) | int(head[28])<<8 | int(head[29])<<16
+		return width + 1, height + 1, true
+	}
+	return 0, 0, false
+}
+
+type dimensions struct {
+	minWidth, maxWidth, minHeight, maxHeight, width, height int
+}
+
+// prepareDimensions parses `minWidth=100,maxHeight=2000`, the keys are width, height and their min and max forms.
+func prepareDimensions(args []string) (interface{}, error) {
+	dim := &dimensions{-1, -1, -1, -1, -1, -1}
+	fields := map[string]*int{
+		"minWidth":  &dim.minWidth,
+		"maxWidth":  &dim.maxWidth,
+		"minHeight": &dim.minHeight,
+		"maxHeight": &dim.maxHeight,
+		"width":     &dim.width,
+		"height":    &dim.height,
+	}
+	for _, arg := range args {
+		parts := strings.SplitN(arg, "=", 2)
+		field, ok := fields[strings.TrimSpace(parts[0])]
+		if !ok || len(parts) != 2 {
+			return nil, fmt.Errorf("unknown dimension %q", arg)
+		}
+		value, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil || value < 0 {
+			return nil, fmt.Errorf("malformed dimension %q", arg)
+		}
+		*field = value
+	}
+	return dim, nil
+}
+
+func (dim *dimensions) match(width, height int) bool {
+	return !(dim.minWidth >= 0 && width < dim.minWidth || dim.maxWidth >= 0 && width > dim.maxWidth ||
+		dim.minHeight >= 0 && height < dim.minHeight || dim.maxHeight >= 0 && height > dim.maxHeight ||
+		dim.width >= 0 && width != dim.width || dim.height >= 0 && height != dim.height)
+}
+
+// prepareRatio parses ratios written as `16/9` or `1.5`.
+func prepareRatio(args []string) (interface{}, error) {
+	parts := strings.SplitN(args[0], "/", 2)
+	numerator, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	denominator := 1.0
+	if err == nil && len(parts) == 2 {
+		denominator, err = strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	}
+	if err != nil || numerator <= 0 || denominator <= 0 {
+		return nil, fmt.Errorf("malformed ratio %q", args[0])
+	}
+	return numerator / denominator, nil
+}
+
+func imageValidators() map[string]validatorFunc {
+	rules := fileValidators()
+	rules["dimensions"] = func(context *phaseContext, obj subjectObj) error {
+		if context.value == nil {
+			return nil
+		}
+		file := context.value.(*File)
+		if !context.prepared.(*dimensions).match(file.Width, file.Height) {
+			context.hasError = true
+			context.err = context.translate("image.dimensions", context.attribute(context.name), file.Width, file.Height)
+		}
+		return nil
+	}
+	rules["ratio"] = func(context *phaseContext, obj subjectObj) error {
+		if context.value == nil {
+			return nil
+		}
+		file := context.value.(*File)
+		precision := 1 / (math.Max(float64(file.Width), float64(file.Height)) + 1)
+		if file.Height == 0 || math.Abs(float64(file.Width)/float64(file.Height)-context.prepared.(float64)) > precision {
+			context.hasError = true
+			context.err = context.translate("image.ratio", context.attribute(context.name), context.args[0])
+		}
+		return nil
+	}
+	rules["square"] = func(context *phaseContext, obj subjectObj) error {
+		if context.value == nil {
+			return nil
+		}
+		file := context.value.(*File)
+		if file.Width != file.Height {
+			context.hasError = true
+			context.err = context.translate("image.square", context.attribute(context.name))
+		}
+		return nil
+	}
+	return rules
+}
@@ -0,0 +1,220 @@
+package vgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// integerRule returns the number rule name for an int or uint field, it is looked up when a schema is compiled so
+// rules registered for numbers later on apply to integers as well.
+func integerRule(name string) (validatorFunc, bool) {
+	fn, ok := validators["number"].(map[string]validatorFunc)[name]
+	if !ok {
+		return nil, false
+	}
+	if compare, ok := integerComparisons[name]; ok {
+		return compareInteger(name, compare, asFloat(fn)), true
+	}
+	return asFloat(fn), true
+}
+
+// integerComparisons check the exact value of an integer field against the arguments of the number rule of the same
+// name, so ids above 2^53 are not rounded like a float64.
+var integerComparisons = map[string]func(value *big.Int, args []*big.Rat) bool{
+	"in": func(value *big.Int, args []*big.Rat) bool {
+		for _, arg := range args {
+			if compareRat(value, arg) == 0 {
+				return true
+			}
+		}
+		return false
+	},
+	"digits": func(value *big.Int, args []*big.Rat) bool {
+		return compareRat(digitCount(value), args[0]) == 0
+	},
+	"digitsBetween": func(value *big.Int, args []*big.Rat) bool {
+		count := digitCount(value)
+		return compareRat(count, args[0]) >= 0 && compareRat(count, args[1]) <= 0
+	},
+	"greaterThan": func(value *big.Int, args []*big.Rat) bool {
+		return compareRat(value, args[0]) > 0
+	},
+	"greaterThanOrEqual": func(value *big.Int, args []*big.Rat) bool {
+		return compareRat(value, args[0]) >= 0
+	},
+	"lessThan": func(value *big.Int, args []*big.Rat) bool {
+		return compareRat(value, args[0]) < 0
+	},
+	"lessThanOrEqual": func(value *big.Int, args []*big.Rat) bool {
+		return compareRat(value, args[0]) <= 0
+	},
+	"between": func(value *big.Int, args []*big.Rat) bool {
+		return compareRat(value, args[0]) >= 0 && compareRat(value, args[1]) <= 0
+	},
+}
+
+func compareRat(value *big.Int, arg *big.Rat) int {
+	return new(big.Rat).SetInt(value).Cmp(arg)
+}
+
+func digitCount(value *big.Int) *big.Int {
+	return big.NewInt(int64(len(new(big.Int).Abs(value).String())))
+}
+
+// compareInteger runs compare on the exact value of an integer field, arguments that are no exact numbers fall back
+// to the float64 rule.
+func compareInteger(name string, compare func(*big.Int, []*big.Rat) bool, fallback validatorFunc) validatorFunc {
+	return func(context *phaseContext, obj subjectObj) error {
+		if context.value == nil {
+			return nil
+		}
+		value, ok := parseInteger(context.value)
+		if !ok {
+			return fallback(context, obj)
+		}
+		args := make([]*big.Rat, len(context.args))
+		for i, arg := range context.args {
+			if args[i], ok = new(big.Rat).SetString(strings.TrimSpace(arg)); !ok {
+				return fallback(context, obj)
+			}
+		}
+		if compare(value, args) {
+			return nil
+		}
+		params := []interface{}{context.attribute(context.name)}
+		if name != "in" {
+			for _, arg := range context.args {
+				params = append(params, arg)
+			}
+		}
+		context.hasError = true
+		context.err = context.translate("number."+name, params...)
+		return nil
+	}
+}
+
+// asFloat runs a number rule on an integer field, the field keeps its integer value.
+func asFloat(fn validatorFunc) validatorFunc {
+	return func(context *phaseContext, obj subjectObj) error {
+		value := context.value
+		switch num := value.(type) {
+		case int64:
+			context.value = float64(num)
+		case uint64:
+			context.value = float64(num)
+		}
+		err := fn(context, obj)
+		context.value = value
+		return err
+	}
+}
+
+// normalizeDigits replaces Persian and Arabic digits with ASCII ones.
+func normalizeDigits(str string) string {
+	return strings.Map(func(char rune) rune {
+		for j, num := range faToEn {
+			if num == char {
+				return '0' + rune(j%10)
+			}
+		}
+		return char
+	}, str)
+}
+
+// parseInteger returns the exact integer value of a number or a decimal integer string, values with a fraction are
+// rejected. JSON numbers like `2.0` or `1e3` are accepted when their value is an integer.
+func parseInteger(value interface{}) (*big.Int, bool) {
+	if str, ok := value.(json.Number); ok {
+		rat, ok := new(big.Rat).SetString(string(str))
+		if !ok || !rat.IsInt() {
+			return nil, false
+		}
+		return rat.Num(), true
+	}
+	val := reflect.ValueOf(value)
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(val.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Int).SetUint64(val.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		num, accuracy := big.NewFloat(val.Float()).Int(nil)
+		return num, accuracy == big.Exact
+	case reflect.String:
+		return new(big.Int).SetString(strings.TrimSpace(normalizeDigits(val.String())), 10)
+	}
+	return nil, false
+}
+
+// convertInteger turns the value of an int field into an int64 and of a uint field into an uint64.
+func convertInteger(context *phaseContext) bool {
+	num, ok := parseInteger(context.value)
+	if !ok || (context.typ == "uint" && num.Sign() < 0) {
+		context.hasError = true
+		context.err = context.translate("type."+context.typ, context.attribute(context.name))
+		return false
+	}
+	if context.typ == "int" && num.IsInt64() {
+		context.value = num.Int64()
+		return true
+	}
+	if context.typ == "uint" && num.IsUint64() {
+		context.value = num.Uint64()
+		return true
+	}
+	context.hasError = true
+	context.code = context.typ + ".overflow"
+	context.err = context.translate("int.overflow", context.attribute(context.name))
+	return false
+}
+
+// decodeJSON decodes a JSON object, numbers of int and uint fields are kept as json.Number for an exact conversion
+// and all other numbers become float64.
+func (s *Schema) decodeJSON(body []byte) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var data map[string]interface{}
+	if err := decoder.Decode(&data); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, ErrMalformedRequest
+	}
+	s.normalizeNumbers(data, nil)
+	return data, nil
+}
+
+func (s *Schema) normalizeNumbers(value interface{}, path []string) interface{} {
+	switch container := value.(type) {
+	case map[string]interface{}:
+		for key, item := range container {
+			container[key] = s.normalizeNumbers(item, append(path[:len(path):len(path)], key))
+		}
+	case []interface{}:
+		for i, item := range container {
+			container[i] = s.normalizeNumbers(item, append(path[:len(path):len(path)], strconv.Itoa(i)))
+		}
+	case json.Number:
+		if field := s.fieldAt(path); field != nil && (field.typ == "int" || field.typ == "uint") {
+			return container
+		}
+		num, _ := container.Float64()
+		return num
+	}
+	return value
+}
+
+// fieldAt returns the field whose path matches path.
+func (s *Schema) fieldAt(path []string) *fieldPlan {
+	for _, field := range s.fields {
+		if len(field.path) == len(path) && matchPattern(field.path, path) {
+			return field
+		}
+	}
+	return nil
+}
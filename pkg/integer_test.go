@@ -0,0 +1,102 @@
+package vgo
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParseInteger(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  string
+		ok    bool
+	}{
+		{"42", "42", true},
+		{" -7 ", "-7", true},
+		{"+7", "7", true},
+		{"۱۲۳", "123", true},
+		{"9007199254740993", "9007199254740993", true},
+		{"4/2", "", false},
+		{"1e3", "", false},
+		{"2.0", "", false},
+		{"0x10", "", false},
+		{"1_000", "", false},
+		{"", "", false},
+		{json.Number("9007199254740993"), "9007199254740993", true},
+		{json.Number("1e3"), "1000", true},
+		{json.Number("2.5"), "", false},
+		{3.0, "3", true},
+		{3.5, "", false},
+		{int8(-3), "-3", true},
+		{uint64(18446744073709551615), "18446744073709551615", true},
+		{true, "", false},
+	}
+	for _, test := range tests {
+		num, ok := parseInteger(test.value)
+		if ok != test.ok || (ok && num.String() != test.want) {
+			t.Errorf("parseInteger(%#v) = %v, %v, want %s, %v", test.value, num, ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestIntegerSeesRegisteredNumberRules(t *testing.T) {
+	RegisterRule("number", "testEven", func(ctx *Context) error {
+		if int64(ctx.Value().(float64))%2 != 0 {
+			return errors.New("odd")
+		}
+		return nil
+	}, "")
+	schema, err := Compile([]string{"i(int) testEven", "u(uint) testEven"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := schema.ValidateJson(`{"i": 4, "u": 6}`); err != nil {
+		t.Error(err)
+	}
+	_, err = schema.ValidateJson(`{"i": 3, "u": 6}`)
+	if errs, ok := err.(ValidationErrors); !ok || errs[0].Rule != "int.testEven" {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestIntegerComparisons(t *testing.T) {
+	tests := []struct {
+		rule  string
+		value string
+		code  string
+	}{
+		{"id(int) lessThanOrEqual(9007199254740992)", "9007199254740993", "int.lessThanOrEqual"},
+		{"id(int) lessThanOrEqual(9007199254740993)", "9007199254740993", ""},
+		{"id(int) lessThan(9007199254740993)", "9007199254740993", "int.lessThan"},
+		{"id(int) greaterThan(9007199254740992)", "9007199254740993", ""},
+		{"id(int) greaterThanOrEqual(9007199254740994)", "9007199254740993", "int.greaterThanOrEqual"},
+		{"id(int) in(9007199254740992)", "9007199254740993", "int.in"},
+		{"id(int) in(1,9007199254740993)", "9007199254740993", ""},
+		{"id(uint) between(18446744073709551614,18446744073709551615)", "18446744073709551615", ""},
+		{"id(uint) between(0,18446744073709551614)", "18446744073709551615", "uint.between"},
+		{"id(int) greaterThan(2.5)", "3", ""},
+		{"id(int) lessThan(2.5)", "3", "int.lessThan"},
+		{"id(int) digits(16)", "9007199254740993", ""},
+		{"id(int) digits(2)", "10", ""},
+		{"id(int) digits(3)", "-123", ""},
+		{"id(int) digitsBetween(1,2)", "100", "int.digitsBetween"},
+		{"id(int) digitsBetween(3,20)", "18446744073709551", ""},
+	}
+	for _, test := range tests {
+		_, err := ValidateJson(`{"id": `+test.value+`}`, []string{test.rule})
+		var code string
+		if errs, ok := err.(ValidationErrors); ok {
+			code = errs[0].Rule
+		} else if err != nil {
+			t.Fatalf("%s: %v", test.rule, err)
+		}
+		if code != test.code {
+			t.Errorf("%s with %s: got %q, want %q", test.rule, test.value, code, test.code)
+		}
+	}
+	_, err := ValidateJson(`{"id": 9007199254740993}`, []string{"id(int) lessThan(9007199254740993)"}, WithLocale(English))
+	if errs, ok := err.(ValidationErrors); !ok || errs[0].Message != "The id must be less than 9007199254740993." {
+		t.Errorf("got %v", err)
+	}
+}
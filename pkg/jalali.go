@@ -0,0 +1,135 @@
+package vgo
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// jalaliBreaks are the years of the 33 year cycle breaks of the Solar Hijri calendar.
+var jalaliBreaks = []int{-61, 9, 38, 199, 426, 686, 756, 818, 1111, 1181, 1210, 1635, 2060, 2097, 2192, 2262, 2324,
+	2394, 2456, 3178}
+
+// jalaliDate matches `1402/07/15`, `1402-7-15` or `1402/07/15 14:30` after the digits are normalized.
+var jalaliDate = regexp.MustCompile(`^(\d{4})[/\-.](\d{1,2})[/\-.](\d{1,2})(?:[ T](\d{1,2}):(\d{2})(?::(\d{2}))?)?$`)
+
+func floorDiv(a, b int) int {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
+
+func floorMod(a, b int) int {
+	return a - floorDiv(a, b)*b
+}
+
+// jalaliYear returns whether the Jalali year jy is a leap year and the day of March of the Gregorian year jy+621
+// that is the first of Farvardin.
+func jalaliYear(jy int) (bool, int) {
+	gy := jy + 621
+	leapJ := -14
+	jp := jalaliBreaks[0]
+	jump := 0
+	for _, jm := range jalaliBreaks[1:] {
+		jump = jm - jp
+		if jy < jm {
+			break
+		}
+		leapJ += floorDiv(jump, 33)*8 + floorDiv(floorMod(jump, 33), 4)
+		jp = jm
+	}
+	n := jy - jp
+	leapJ += floorDiv(n, 33)*8 + floorDiv(floorMod(n, 33)+3, 4)
+	if floorMod(jump, 33) == 4 && jump-n == 4 {
+		leapJ++
+	}
+	leapG := floorDiv(gy, 4) - floorDiv((floorDiv(gy, 100)+1)*3, 4) - 150
+	march := 20 + leapJ - leapG
+	if jump-n < 6 {
+		n = n - jump + floorDiv(jump+4, 33)*33
+	}
+	leap := floorMod(floorMod(n+1, 33)-1, 4)
+	return leap == 0, march
+}
+
+func jalaliMonthDays(jy, jm int) int {
+	switch {
+	case jm <= 6:
+		return 31
+	case jm <= 11:
+		return 30
+	}
+	if leap, _ := jalaliYear(jy); leap {
+		return 30
+	}
+	return 29
+}
+
+// jalaliToTime returns the midnight of a valid Jalali date in loc.
+func jalaliToTime(jy, jm, jd int, loc *time.Location) (time.Time, error) {
+	if jy < 1 || jy >= jalaliBreaks[len(jalaliBreaks)-1] || jm < 1 || jm > 12 || jd < 1 || jd > jalaliMonthDays(jy, jm) {
+		return time.Time{}, fmt.Errorf("invalid jalali date %d/%d/%d", jy, jm, jd)
+	}
+	_, march := jalaliYear(jy)
+	day := jd - 1
+	if jm <= 7 {
+		day += (jm - 1) * 31
+	} else {
+		day += 186 + (jm-7)*30
+	}
+	return time.Date(jy+621, time.March, march+day, 0, 0, 0, 0, loc), nil
+}
+
+// timeToJalali returns the Jalali year, month and day of t in its location.
+func timeToJalali(t time.Time) (int, int, int) {
+	year, month, day := t.Date()
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	jy := year - 621
+	_, march := jalaliYear(jy)
+	start := time.Date(year, time.March, march, 0, 0, 0, 0, time.UTC)
+	if date.Before(start) {
+		jy--
+		_, march = jalaliYear(jy)
+		start = time.Date(year-1, time.March, march, 0, 0, 0, 0, time.UTC)
+	}
+	days := int(date.Sub(start).Hours() / 24)
+	if days < 186 {
+		return jy, days/31 + 1, days%31 + 1
+	}
+	days -= 186
+	return jy, days/30 + 7, days%30 + 1
+}
+
+// parseJalali parses a Jalali date written with ASCII, Persian or Arabic digits. matched reports whether value has
+// the shape of a date, in which case err tells whether it is a valid one.
+func parseJalali(value string, loc *time.Location) (tm time.Time, matched bool, err error) {
+	match := jalaliDate.FindStringSubmatch(strings.TrimSpace(normalizeDigits(value)))
+	if match == nil {
+		return time.Time{}, false, nil
+	}
+	parts := make([]int, 6)
+	for i, part := range match[1:] {
+		if part != "" {
+			parts[i], _ = strconv.Atoi(part)
+		}
+	}
+	tm, err = jalaliToTime(parts[0], parts[1], parts[2], loc)
+	if err != nil {
+		return tm, true, err
+	}
+	if parts[3] > 23 || parts[4] > 59 || parts[5] > 59 {
+		return time.Time{}, true, fmt.Errorf("invalid time in %q", value)
+	}
+	year, month, day := tm.Date()
+	return time.Date(year, month, day, parts[3], parts[4], parts[5], 0, loc), true, nil
+}
+
+// formatJalali renders t like formatDate with the Jalali calendar.
+func formatJalali(t time.Time) string {
+	jy, jm, jd := timeToJalali(t)
+	return fmt.Sprintf("%04d/%02d/%02d %02d:%02d", jy, jm, jd, t.Hour(), t.Minute())
+}
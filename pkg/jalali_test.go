@@ -0,0 +1,68 @@
+package vgo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseJalali(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Time
+		valid bool
+	}{
+		{"1403/01/01", time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC), true},
+		{"1402/01/01", time.Date(2023, 3, 21, 0, 0, 0, 0, time.UTC), true},
+		{"1404/01/01", time.Date(2025, 3, 21, 0, 0, 0, 0, time.UTC), true},
+		{"1357/11/22", time.Date(1979, 2, 11, 0, 0, 0, 0, time.UTC), true},
+		{"1403/12/30", time.Date(2025, 3, 20, 0, 0, 0, 0, time.UTC), true},
+		{"1399/12/30", time.Date(2021, 3, 20, 0, 0, 0, 0, time.UTC), true},
+		{"1402/12/30", time.Time{}, false},
+		{"1402/07/31", time.Time{}, false},
+		{"1402/06/31", time.Date(2023, 9, 22, 0, 0, 0, 0, time.UTC), true},
+		{"1402-7-15", time.Date(2023, 10, 7, 0, 0, 0, 0, time.UTC), true},
+		{"۱۴۰۲/۰۷/۱۵", time.Date(2023, 10, 7, 0, 0, 0, 0, time.UTC), true},
+		{"١٤٠٢/٠٧/١٥", time.Date(2023, 10, 7, 0, 0, 0, 0, time.UTC), true},
+		{"1402/07/15 14:30", time.Date(2023, 10, 7, 14, 30, 0, 0, time.UTC), true},
+		{"1402/07/15 24:00", time.Time{}, false},
+		{"1402/13/01", time.Time{}, false},
+	}
+	for _, test := range tests {
+		tm, matched, err := parseJalali(test.value, time.UTC)
+		if !matched {
+			t.Errorf("%s: not matched", test.value)
+			continue
+		}
+		if (err == nil) != test.valid {
+			t.Errorf("%s: got error %v, want valid %v", test.value, err, test.valid)
+			continue
+		}
+		if test.valid && !tm.Equal(test.want) {
+			t.Errorf("%s: got %v, want %v", test.value, tm, test.want)
+		}
+	}
+	if _, matched, _ := parseJalali("2023-10-07T00:00:00Z", time.UTC); matched {
+		t.Error("RFC3339 date matched as jalali")
+	}
+}
+
+func TestJalaliRoundTrip(t *testing.T) {
+	day := time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2150, 1, 1, 0, 0, 0, 0, time.UTC)
+	py, pm, pd := timeToJalali(day.AddDate(0, 0, -1))
+	for ; day.Before(end); day = day.AddDate(0, 0, 1) {
+		jy, jm, jd := timeToJalali(day)
+		back, err := jalaliToTime(jy, jm, jd, time.UTC)
+		if err != nil || !back.Equal(day) {
+			t.Fatalf("%s: %d/%d/%d converts back to %v, %v", day.Format("2006-01-02"), jy, jm, jd, back, err)
+		}
+		switch {
+		case jy == py && jm == pm && jd == pd+1:
+		case jy == py && jm == pm+1 && jd == 1 && pd == jalaliMonthDays(py, pm):
+		case jy == py+1 && jm == 1 && jd == 1 && pm == 12 && pd == jalaliMonthDays(py, pm):
+		default:
+			t.Fatalf("%s: %d/%d/%d does not follow %d/%d/%d", day.Format("2006-01-02"), jy, jm, jd, py, pm, pd)
+		}
+		py, pm, pd = jy, jm, jd
+	}
+}
@@ -0,0 +1,108 @@
+package vgo
+
+import (
+	"fmt"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+)
+
+// lengthLimits are the prepared arguments of the string length rules, mode is empty for code points,
+// `graphemes` for user perceived characters or `bytes` for the UTF-8 size.
+type lengthLimits struct {
+	limits []int
+	mode   string
+}
+
+// prepareLength returns the preparer of a length rule with count limits, a trailing `runes`, `graphemes` or `bytes`
+// argument selects how to count.
+func prepareLength(count int) func(args []string) (interface{}, error) {
+	return func(args []string) (interface{}, error) {
+		spec := &lengthLimits{}
+		if n := len(args) - 1; n >= count {
+			switch args[n] {
+			case "runes":
+				args = args[:n]
+			case "graphemes", "bytes":
+				spec.mode = args[n]
+				args = args[:n]
+			default:
+				return nil, fmt.Errorf("unknown length mode %q", args[n])
+			}
+		}
+		for _, arg := range args {
+			limit, err := strconv.Atoi(arg)
+			if err != nil || limit < 0 {
+				return nil, fmt.Errorf("bad length %q", arg)
+			}
+			spec.limits = append(spec.limits, limit)
+		}
+		if len(spec.limits) != count {
+			return nil, fmt.Errorf("bad argument count %d", len(args))
+		}
+		return spec, nil
+	}
+}
+
+// lengthRule returns a string rule that fails when fails reports true for the length of the value.
+func lengthRule(key string, fails func(length int, limits []int) bool) validatorFunc {
+	return func(context *phaseContext, obj subjectObj) error {
+		str, ok := context.value.(string)
+		if !ok {
+			return nil
+		}
+		spec := context.prepared.(*lengthLimits)
+		if !fails(stringLength(str, spec.mode), spec.limits) {
+			return nil
+		}
+		msgKey := key
+		if spec.mode != "" {
+			msgKey += "." + spec.mode
+		}
+		args := []interface{}{context.attribute(context.name)}
+		for _, limit := range spec.limits {
+			args = append(args, limit)
+		}
+		context.hasError = true
+		context.err = context.translate(msgKey, args...)
+		return nil
+	}
+}
+
+func stringLength(str string, mode string) int {
+	switch mode {
+	case "bytes":
+		return len(str)
+	case "graphemes":
+		return graphemeCount(str)
+	}
+	return utf8.RuneCountInString(str)
+}
+
+// graphemeCount approximates the number of user perceived characters: combining marks, variation selectors, emoji
+// modifiers and tags, characters joined by a zero width joiner, CRLF and regional indicator pairs count once.
+func graphemeCount(str string) int {
+	count := 0
+	joined := false
+	regional := false
+	var last rune
+	for _, char := range str {
+		extends := unicode.In(char, unicode.Mn, unicode.Me, unicode.Mc) ||
+			(char >= 0xFE00 && char <= 0xFE0F) || (char >= 0xE0100 && char <= 0xE01EF) ||
+			(char >= 0x1F3FB && char <= 0x1F3FF) || (char >= 0xE0020 && char <= 0xE007F) || char == 0x200D
+		isRegional := char >= 0x1F1E6 && char <= 0x1F1FF
+		switch {
+		case count == 0:
+			count++
+		case extends, joined, char == '\n' && last == '\r':
+		case isRegional && regional:
+			isRegional = false
+		default:
+			count++
+		}
+		joined = char == 0x200D
+		regional = isRegional
+		last = char
+	}
+	return count
+}
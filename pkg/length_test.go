@@ -0,0 +1,99 @@
+package vgo
+
+import "testing"
+
+func TestGraphemeCount(t *testing.T) {
+	tests := []struct {
+		str  string
+		want int
+	}{
+		{"", 0},
+		{"abc", 3},
+		{"محمدرضا", 7},
+		{"e\u0301", 1},
+		{"👍🏽", 1},
+		{"👨\u200d👩\u200d👧", 1},
+		{"🇮🇷🇩🇪", 2},
+		{"🇮🇷x", 2},
+		{"\u2764\ufe0f", 1},
+		{"a\r\nb", 3},
+		{"\u0301a", 2},
+	}
+	for _, test := range tests {
+		if got := graphemeCount(test.str); got != test.want {
+			t.Errorf("%q: got %d, want %d", test.str, got, test.want)
+		}
+	}
+}
+
+func TestStringLength(t *testing.T) {
+	tests := []struct {
+		rule  string
+		value string
+		code  string
+	}{
+		{"s(string) size(7)", "محمدرضا", ""},
+		{"s(string) max(6)", "محمدرضا", "string.max"},
+		{"s(string) min(2)", "ab", ""},
+		{"s(string) min(3,runes)", "ab", "string.min"},
+		{"s(string) between(1,2)", "abc", "string.between"},
+		{"s(string) size(1)", "👍🏽", "string.size"},
+		{"s(string) size(1,graphemes)", "👍🏽", ""},
+		{"s(string) between(2,3,graphemes)", "e\u0301", "string.between"},
+		{"s(string) max(13,bytes)", "محمدرضا", "string.max"},
+		{"s(string) max(14,bytes)", "محمدرضا", ""},
+		{"s(string) min(8,bytes)", "👍🏽", ""},
+	}
+	for _, test := range tests {
+		_, err := ValidateJson(`{"s": "`+test.value+`"}`, []string{test.rule})
+		var code string
+		if errs, ok := err.(ValidationErrors); ok {
+			code = errs[0].Rule
+		} else if err != nil {
+			t.Fatalf("%s: %v", test.rule, err)
+		}
+		if code != test.code {
+			t.Errorf("%s with %q: got %q, want %q", test.rule, test.value, code, test.code)
+		}
+	}
+}
+
+func TestStringLengthMessages(t *testing.T) {
+	tests := []struct {
+		rule string
+		want string
+	}{
+		{"s(string) min(5)", "The s must be at least 5 characters."},
+		{"s(string) min(5,graphemes)", "The s must be at least 5 characters."},
+		{"s(string) between(5,9,bytes)", "The s must be between 5 and 9 bytes."},
+	}
+	for _, test := range tests {
+		_, err := ValidateJson(`{"s": "abc"}`, []string{test.rule}, WithLocale(English))
+		errs, ok := err.(ValidationErrors)
+		if !ok {
+			t.Fatalf("%s: got %v", test.rule, err)
+		}
+		if errs[0].Message != test.want {
+			t.Errorf("%s: got %q, want %q", test.rule, errs[0].Message, test.want)
+		}
+	}
+}
+
+func TestStringLengthArguments(t *testing.T) {
+	tests := []struct {
+		rule string
+		ok   bool
+	}{
+		{"s(string) max(5,graphemes)", true},
+		{"s(string) between(1,5,bytes)", true},
+		{"s(string) max(5,words)", false},
+		{"s(string) max(-1)", false},
+		{"s(string) between(1)", false},
+		{"s(string) size(graphemes)", false},
+	}
+	for _, test := range tests {
+		if _, err := Compile([]string{test.rule}); (err == nil) != test.ok {
+			t.Errorf("%s: got %v", test.rule, err)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package vgo
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Translator renders validation messages and field names.
+type Translator interface {
+	Translate(key string, args ...interface{}) string
+	Attribute(name string) string
+}
+
+// Locale is a message catalog. Messages are fmt formats keyed like `string.min`, Attributes maps field names to display names.
+// Jalali renders the dates of messages with the Jalali calendar.
+type Locale struct {
+	Name       string
+	Messages   map[string]string
+	Attributes map[string]string
+	Jalali     bool
+}
+
+func (l *Locale) Translate(key string, args ...interface{}) string {
+	trs, ok := l.Messages[key]
+	if !ok {
+		trs = l.Messages["none"]
+	}
+	return fmt.Sprintf(trs, args...)
+}
+
+func (l *Locale) Attribute(name string) string {
+	val, ok := l.Attributes[name]
+	if ok {
+		return val
+	}
+	return name
+}
+
+// Persian is the default locale.
+var Persian = &Locale{
+	Name:       "fa",
+	Messages:   translations,
+	Attributes: attributes,
+	Jalali:     true,
+}
+
+// English is the English catalog.
+var English = &Locale{
+	Name: "en",
+	Messages: map[string]string{
+		"present":            "The %s field must be present.",
+		"required":           "The %s field is required.",
+		"requiredWith":       "The %[2]s field is required when %[1]v is present.",
+		"requiredWithAll":    "The %[2]s field is required when %[1]v are present.",
+		"requiredWithout":    "The %[2]s field is required when %[1]v is not present.",
+		"requiredWithoutAll": "The %[2]s field is required when none of %[1]v are present.",
+		"requiredIf":         "The %s field is required when %s is %v.",
+		"requiredUnless":     "The %s field is required unless %s is in %v.",
+		"prohibited":         "The %s field is prohibited.",
+		"prohibitedIf":       "The %s field is prohibited when %s is %v.",
+		"prohibitedUnless":   "The %s field is prohibited unless %s is in %v.",
+		"accepted":           "The %s must be accepted.",
+		"declined":           "The %s must be declined.",
+		"confirmed":          "The %s confirmation does not match.",
+		"none":               "The %s field is invalid.",
+		"unknown":            "The %s field is not allowed.",
+		"same":               "The %s and %s must match.",
+		"different":          "The %s and %s must be different.",
+
+		"string.national":          "The %s must be a valid national code.",
+		"string.filled":            "The %s field must have a value.",
+		"string.in":                "The selected %s is invalid.",
+		"string.inArray":           "The %s field does not exist in %s.",
+		"string.notIn":             "The selected %s is invalid.",
+		"string.url":               "The %s format is invalid.",
+		"string.uuid":              "The %s must be a valid UUID.",
+		"string.email":             "The %s must be a valid email address.",
+		"string.mobile":            "The %s must be a valid mobile number.",
+		"string.phone":             "The %s must be a valid phone number.",
+		"string.ip":                "The %s must be a valid IP address.",
+		"string.ipv4":              "The %s must be a valid IPv4 address.",
+		"string.ipv6":              "The %s must be a valid IPv6 address.",
+		"string.json":              "The %s must be a valid JSON string.",
+		"string.size":              "The %s must be %v characters.",
+		"string.min":               "The %s must be at least %v characters.",
+		"string.max":               "The %s may not be greater than %v characters.",
+		"string.between":           "The %s must be between %v and %v characters.",
+		"string.size.graphemes":    "The %s must be %v characters.",
+		"string.min.graphemes":     "The %s must be at least %v characters.",
+		"string.max.graphemes":     "The %s may not be greater than %v characters.",
+		"string.between.graphemes": "The %s must be between %v and %v characters.",
+		"string.size.bytes":        "The %s must be %v bytes.",
+		"string.min.bytes":         "The %s must be at least %v bytes.",
+		"string.max.bytes":         "The %s may not be greater than %v bytes.",
+		"string.between.bytes":     "The %s must be between %v and %v bytes.",
+		"string.regex":             "The %s format is invalid.",
+		"string.username":          "The %s may only contain letters, numbers, dashes and underscores.",
+		"string.alphaNum":          "The %s may only contain letters and numbers.",
+		"string.persian":           "The %s may only contain Persian letters.",
+		"string.alpha":             "The %s may only contain letters.",
+		"string.startsWith":        "The %s must start with one of the following: %s",
+		"string.endsWith":          "The %s must end with one of the following: %s",
+		"string.contains":          "The %s must contain one of the following: %s",
+
+		"number.digits":             "The %s must be %v digits.",
+		"number.digitsBetween":      "The %s must be between %v and %v digits.",
+		"number.greaterThan":        "The %s must be greater than %v.",
+		"number.greaterThanOrEqual": "The %s must be greater than or equal to %v.",
+		"number.lessThan":           "The %s must be less than %v.",
+		"number.lessThanOrEqual":    "The %s must be less than or equal to %v.",
+		"number.between":            "The %s must be between %v and %v.",
+		"number.in":                 "The selected %s is invalid.",
+		"int.overflow":              "The %s is out of range.",
+
+		"date.after":   "The %s(%v) must be a date after %v.",
+		"date.before":  "The %s(%v) must be a date before %v.",
+		"date.between": "The %s(%v) must be a date between %v and %v.",
+
+		"file.mimeMismatch": "The %s content does not match its declared type.",
+		"file.maxSize":      "The %s may not be greater than %v.",
+		"file.minSize":      "The %s must be at least %v.",
+		"file.mimes":        "The %s must be a file of type: %v",
+		"file.extensions":   "The %s must have one of the following extensions: %v",
+
+		"image.dimensions": "The %s has invalid dimensions (%vx%v).",
+		"image.ratio":      "The %s must have an aspect ratio of %v.",
+		"image.square":     "The %s must be square.",
+
+		"array.minItems":      "The %s must have at least %v items.",
+		"array.maxItems":      "The %s may not have more than %v items.",
+		"array.sizeItems":     "The %s must contain %v items.",
+		"array.distinct":      "The %s field has a duplicate value.",
+		"array.containsValue": "The %s must contain %v.",
+
+		"type.string": "The %s must be a string.",
+		"type.array":  "The %s must be an array.",
+		"type.object": "The %s must be an object.",
+		"type.number": "The %s must be a number or a string of digits.",
+		"type.int":    "The %s must be an integer.",
+		"type.uint":   "The %s must be a non-negative integer.",
+		"type.bool":   "The %s field must be true or false.",
+		"type.file":   "The %s must be a valid file.",
+		"type.image":  "The %s must be a valid image.",
+		"type.date":   "The %s is not a valid date.",
+		"type.none":   "The %s field is misconfigured on the server.",
+	},
+	Attributes: map[string]string{},
+}
+
+var locales = map[string]*Locale{
+	"fa": Persian,
+	"en": English,
+}
+var localesMutex sync.RWMutex
+
+// RegisterLocale makes locale available to LookupLocale and MatchLocale under its name.
+func RegisterLocale(locale *Locale) {
+	localesMutex.Lock()
+	defer localesMutex.Unlock()
+	locales[strings.ToLower(locale.Name)] = locale
+}
+
+// LookupLocale returns the locale registered as name, e.g. `en` or `fa`.
+func LookupLocale(name string) *Locale {
+	localesMutex.RLock()
+	defer localesMutex.RUnlock()
+	return locales[strings.ToLower(name)]
+}
+
+// MatchLocale returns the registered locale that best matches an Accept-Language header value, or nil.
+func MatchLocale(acceptLanguage string) *Locale {
+	type candidate struct {
+		tag     string
+		quality float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		params := strings.Split(strings.TrimSpace(part), ";")
+		item := candidate{tag: strings.TrimSpace(params[0]), quality: 1}
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					item.quality = q
+				}
+			}
+		}
+		if item.tag != "" && item.quality > 0 {
+			candidates = append(candidates, item)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+	for _, item := range candidates {
+		if locale := LookupLocale(item.tag); locale != nil {
+			return locale
+		}
+		if i := strings.IndexAny(item.tag, "-_"); i > 0 {
+			if locale := LookupLocale(item.tag[:i]); locale != nil {
+				return locale
+			}
+		}
+	}
+	return nil
+}
+
+func (context *phaseContext) translate(key string, args ...interface{}) string {
+	return context.locale.Translate(key, args...)
+}
+
+func (context *phaseContext) attribute(name string) string {
+	return context.locale.Attribute(name)
+}
+
+func (context *phaseContext) attributes(names ...string) []string {
+	out := make([]string, len(names))
+	for i, name := range names {
+		out[i] = context.locale.Attribute(name)
+	}
+	return out
+}
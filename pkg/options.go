@@ -0,0 +1,96 @@
+package vgo
+
+import "time"
+
+// Option configures a Schema when passed to Compile, or a single validation when passed to Validate.
+type Option func(*options)
+
+type options struct {
+	allErrors   bool
+	unknown     unknownMode
+	locale      Translator
+	location    *time.Location
+	maxBodySize int64
+	errorWriter ErrorWriter
+}
+
+// unknownMode is what happens to input keys that no rule covers.
+type unknownMode int
+
+const (
+	allowUnknown unknownMode = iota
+	rejectUnknown
+	stripUnknown
+)
+
+// AllErrors keeps evaluating the remaining rules of a field after a failure, errors are then reported as a list of messages per field.
+func AllErrors() Option {
+	return func(o *options) {
+		o.allErrors = true
+	}
+}
+
+// FirstError stops at the first failing rule of each field, this is the default.
+func FirstError() Option {
+	return func(o *options) {
+		o.allErrors = false
+	}
+}
+
+// RejectUnknown reports every input key that is not covered by a rule as an error of its own.
+func RejectUnknown() Option {
+	return func(o *options) {
+		o.unknown = rejectUnknown
+	}
+}
+
+// StripUnknown removes the keys that are not covered by a rule from the values of object and array fields.
+func StripUnknown() Option {
+	return func(o *options) {
+		o.unknown = stripUnknown
+	}
+}
+
+// AllowUnknown ignores keys that are not covered by a rule, this is the default.
+func AllowUnknown() Option {
+	return func(o *options) {
+		o.unknown = allowUnknown
+	}
+}
+
+// WithLocale renders messages and attribute names with t, the Persian catalog is used by default.
+func WithLocale(t Translator) Option {
+	return func(o *options) {
+		o.locale = t
+	}
+}
+
+// WithLanguage picks the registered locale that best matches an Accept-Language header value.
+func WithLanguage(acceptLanguage string) Option {
+	return func(o *options) {
+		if locale := MatchLocale(acceptLanguage); locale != nil {
+			o.locale = locale
+		}
+	}
+}
+
+// WithLocation resolves `today` and the other relative dates in loc and returns dates in loc, UTC is used by default.
+func WithLocation(loc *time.Location) Option {
+	return func(o *options) {
+		o.location = loc
+	}
+}
+
+func (o options) translator() Translator {
+	if o.locale == nil {
+		return Persian
+	}
+	return o.locale
+}
+
+func (o options) with(opts []Option) options {
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
@@ -1,101 +1,160 @@
 package vgo
 
 import (
+	"fmt"
 	"strings"
 	"time"
+	"unicode"
 )
 
-func removeSlashes(val string) string {
-	val = strings.ReplaceAll(val, "\\)", ")")
-	val = strings.ReplaceAll(val, "\\(", "(")
-	val = strings.ReplaceAll(val, "\\,", ",")
-	return strings.Trim(val, " ")
+func parseDate(val string) (time.Time, error) {
+	return resolveDate(val, time.UTC)
 }
-func parseDate(val string) (time.Time, error)  {
-	if val == "now" {
-		return time.Now().UTC(), nil
+
+// SyntaxError reports a malformed rule string.
+type SyntaxError struct {
+	// Index is the zero based position of the rule in the compiled list.
+	Index int
+	// Column is the one based column, counted in runes, where the error was found.
+	Column int
+	Rule   string
+	// Token is the part of the rule that was being parsed.
+	Token string
+	Msg   string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("rule %d, col %d: %s in %q", e.Index+1, e.Column, e.Msg, e.Token)
+}
+
+// Snippet returns the rule with a caret under the offending column.
+func (e *SyntaxError) Snippet() string {
+	return e.Rule + "\n" + strings.Repeat(" ", e.Column-1) + "^"
+}
+
+type ruleCall struct {
+	name   string
+	args   []string
+	column int
+}
+
+type ruleParser struct {
+	rule  []rune
+	pos   int
+	start int
+}
+
+func (p *ruleParser) fail(at int, msg string) *SyntaxError {
+	end := p.pos
+	if end > len(p.rule) {
+		end = len(p.rule)
+	}
+	return &SyntaxError{
+		Column: at + 1,
+		Rule:   string(p.rule),
+		Token:  strings.TrimSpace(string(p.rule[p.start:end])),
+		Msg:    msg,
+	}
+}
+
+// parseRule splits a rule string like `name(string) required min(5)` into calls.
+func parseRule(rule string) ([]ruleCall, error) {
+	p := &ruleParser{rule: []rune(rule)}
+	var calls []ruleCall
+	for {
+		for p.pos < len(p.rule) && unicode.IsSpace(p.rule[p.pos]) {
+			p.pos++
+		}
+		if p.pos >= len(p.rule) {
+			return calls, nil
+		}
+		call, err := p.call()
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, call)
+		if p.pos < len(p.rule) && !unicode.IsSpace(p.rule[p.pos]) {
+			p.pos++
+			return nil, p.fail(p.pos-1, fmt.Sprintf("expected space before %q", p.rule[p.pos-1]))
+		}
 	}
-	if val == "today" {
-		t := time.Now().UTC()
-		year, month, day := t.Date()
-		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC), nil
+}
+
+func (p *ruleParser) call() (ruleCall, error) {
+	p.start = p.pos
+	call := ruleCall{column: p.pos + 1}
+	for p.pos < len(p.rule) {
+		char := p.rule[p.pos]
+		if unicode.IsSpace(char) || char == '(' {
+			break
+		}
+		switch char {
+		case ')':
+			p.pos++
+			return call, p.fail(p.pos-1, "unexpected ')'")
+		case ',':
+			p.pos++
+			return call, p.fail(p.pos-1, "unexpected ','")
+		case '\\':
+			p.pos++
+			return call, p.fail(p.pos-1, "unexpected '\\'")
+		}
+		p.pos++
 	}
-	if val == "yesterday" {
-		t := time.Now().UTC().Add(time.Hour*-24)
-		year, month, day := t.Date()
-		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC), nil
+	call.name = string(p.rule[p.start:p.pos])
+	if p.pos >= len(p.rule) || p.rule[p.pos] != '(' {
+		return call, nil
 	}
-	if val == "tomorrow" {
-		t := time.Now().UTC().Add(time.Hour*24)
-		year, month, day := t.Date()
-		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC), nil
+	if call.name == "" {
+		p.pos++
+		return call, p.fail(p.pos-1, "missing rule name")
 	}
-	tme, err := time.Parse(time.RFC3339, val)
+	open := p.pos
+	p.pos++
+	args, err := p.args(open)
 	if err != nil {
-		return tme, err
+		return call, err
 	}
-	return tme.UTC(), nil
+	call.args = args
+	return call, nil
 }
 
-func evalRuleChain(rule string, call func(name string, args ...string) bool) {
-	scopeBlock := 0
-	cut := 0
-	inBlock := false
-	before := ' '
-	ln := len(rule)
-	paramStart := 0
-	argStart := 0
-	haveArguments := false
+func (p *ruleParser) args(open int) ([]string, error) {
 	var args []string
-	for i, char := range rule {
-		if before != '\\' {
-			if char == '(' {
-				if scopeBlock == 0 {
-					haveArguments = true
-					paramStart = i
-					argStart = i + 1
-					inBlock = true
-				}
-				scopeBlock++
-			} else if char == ')' {
-				scopeBlock--
-				if scopeBlock == 0 {
-					args = append(args, removeSlashes(rule[argStart:i]))
-					argStart = i
-					inBlock = false
-					name := rule[cut-1 : paramStart]
-					if !call(name, args...) {
-						return
-					}
-					args = []string{}
-				}
-			} else if char == ',' && inBlock {
-				args = append(args, removeSlashes(rule[argStart:i]))
-				argStart = i + 1
+	var arg []rune
+	depth := 0
+	for p.pos < len(p.rule) {
+		char := p.rule[p.pos]
+		p.pos++
+		switch {
+		case char == '\\':
+			if p.pos >= len(p.rule) {
+				return nil, p.fail(p.pos-1, "trailing backslash")
 			}
-		}
-		if !inBlock {
-			if before != ' ' {
-				if char == ' ' || i == ln-1 {
-					begin := cut - 1
-					if i == ln-1 {
-						cut = ln + 1
-					} else {
-						cut = i + 1
-					}
-					if !haveArguments {
-						name := rule[begin : cut-1]
-						if !call(name) {
-							return
-						}
-					}
-					haveArguments = false
-				}
+			next := p.rule[p.pos]
+			if next == '(' || next == ')' || next == ',' {
+				arg = append(arg, next)
+				p.pos++
 			} else {
-				cut++
+				arg = append(arg, char)
+			}
+		case char == '(':
+			depth++
+			arg = append(arg, char)
+		case char == ')' && depth > 0:
+			depth--
+			arg = append(arg, char)
+		case char == ')':
+			if len(args) > 0 || strings.TrimSpace(string(arg)) != "" {
+				args = append(args, strings.TrimSpace(string(arg)))
 			}
+			return args, nil
+		case char == ',' && depth == 0:
+			args = append(args, strings.TrimSpace(string(arg)))
+			arg = arg[:0]
+		default:
+			arg = append(arg, char)
 		}
-		before = char
 	}
+	return nil, p.fail(open, "unclosed '('")
 }
-
@@ -0,0 +1,87 @@
+package vgo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		rule string
+		want []ruleCall
+	}{
+		{"", nil},
+		{"name(string) required  min(5)", []ruleCall{
+			{name: "name", args: []string{"string"}, column: 1},
+			{name: "required", column: 14},
+			{name: "min", args: []string{"5"}, column: 24},
+		}},
+		{"x()", []ruleCall{{name: "x", column: 1}}},
+		{"x( a , b )", []ruleCall{{name: "x", args: []string{"a", "b"}, column: 1}}},
+		{"x(a,)", []ruleCall{{name: "x", args: []string{"a", ""}, column: 1}}},
+		{`in(a\,b,c)`, []ruleCall{{name: "in", args: []string{"a,b", "c"}, column: 1}}},
+		{`regex(^\(x\)$)`, []ruleCall{{name: "regex", args: []string{"^(x)$"}, column: 1}}},
+		{`regex(\d+)`, []ruleCall{{name: "regex", args: []string{`\d+`}, column: 1}}},
+		{"tags(array(string(trim)))", []ruleCall{{name: "tags", args: []string{"array(string(trim))"}, column: 1}}},
+		{"x(a(b,c),d)", []ruleCall{{name: "x", args: []string{"a(b,c)", "d"}, column: 1}}},
+		{"نام(string) required", []ruleCall{
+			{name: "نام", args: []string{"string"}, column: 1},
+			{name: "required", column: 13},
+		}},
+	}
+	for _, test := range tests {
+		calls, err := parseRule(test.rule)
+		if err != nil {
+			t.Errorf("%q: %v", test.rule, err)
+			continue
+		}
+		if !reflect.DeepEqual(calls, test.want) {
+			t.Errorf("%q: got %+v, want %+v", test.rule, calls, test.want)
+		}
+	}
+}
+
+func TestParseRuleErrors(t *testing.T) {
+	tests := []struct {
+		rule    string
+		column  int
+		msg     string
+		snippet string
+	}{
+		{"name(string", 5, "unclosed '('", "name(string\n    ^"},
+		{"x(a(b)", 2, "unclosed '('", "x(a(b)\n ^"},
+		{"req)uired", 4, "unexpected ')'", "req)uired\n   ^"},
+		{"a,b", 2, "unexpected ','", "a,b\n ^"},
+		{`a\(b`, 2, `unexpected '\'`, "a\\(b\n ^"},
+		{`x(a\`, 4, "trailing backslash", "x(a\\\n   ^"},
+		{"(string)", 1, "missing rule name", "(string)\n^"},
+		{"x(a)y", 5, `expected space before 'y'`, "x(a)y\n    ^"},
+		{"نام(string", 4, "unclosed '('", "نام(string\n   ^"},
+		{"نام(string) الزامی)", 19, "unexpected ')'", "نام(string) الزامی)\n                  ^"},
+	}
+	for _, test := range tests {
+		_, err := parseRule(test.rule)
+		failure, ok := err.(*SyntaxError)
+		if !ok {
+			t.Errorf("%q: got %v, want a syntax error", test.rule, err)
+			continue
+		}
+		if failure.Column != test.column || failure.Msg != test.msg {
+			t.Errorf("%q: got col %d %q, want col %d %q", test.rule, failure.Column, failure.Msg, test.column, test.msg)
+		}
+		if snippet := failure.Snippet(); snippet != test.snippet {
+			t.Errorf("%q: got snippet\n%s\nwant\n%s", test.rule, snippet, test.snippet)
+		}
+	}
+}
+
+func TestCompileSyntaxError(t *testing.T) {
+	_, err := Compile([]string{"name(string) required", "age(number"})
+	failure, ok := err.(*SyntaxError)
+	if !ok {
+		t.Fatalf("got %v, want a syntax error", err)
+	}
+	if want := `rule 2, col 4: unclosed '(' in "age(number"`; failure.Error() != want {
+		t.Errorf("got %q, want %q", failure.Error(), want)
+	}
+}
@@ -0,0 +1,181 @@
+package vgo
+
+import (
+	"strconv"
+	"strings"
+)
+
+func splitPath(name string) []string {
+	return strings.Split(name, ".")
+}
+
+func childValue(current interface{}, key string) (interface{}, bool) {
+	switch container := current.(type) {
+	case map[string]interface{}:
+		value, ok := container[key]
+		return value, ok
+	case []interface{}:
+		index, err := strconv.Atoi(key)
+		if err != nil || index < 0 || index >= len(container) {
+			return nil, false
+		}
+		return container[index], true
+	}
+	return nil, false
+}
+
+func lookupPath(obj subjectObj, path []string) (interface{}, bool) {
+	var current interface{} = obj
+	for _, key := range path {
+		var ok bool
+		current, ok = childValue(current, key)
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// lookup resolves name from the root of obj, its `*` segments are replaced with the indexes of the field under validation.
+func (context *phaseContext) lookup(obj subjectObj, name string) (interface{}, bool) {
+	return lookupPath(obj, context.resolve(name))
+}
+
+// resolve splits name into a path and replaces its `*` segments with the indexes of the field under validation.
+func (context *phaseContext) resolve(name string) []string {
+	path := splitPath(name)
+	n := 0
+	for i, key := range path {
+		if key == "*" && n < len(context.indexes) {
+			path[i] = context.indexes[n]
+			n++
+		}
+	}
+	return path
+}
+
+func hasWildcard(path []string) bool {
+	for _, key := range path {
+		if key == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// expandPath resolves every `*` segment of path against the elements of the arrays found in obj.
+func expandPath(obj subjectObj, path []string) [][]string {
+	paths := [][]string{{}}
+	for _, key := range path {
+		var next [][]string
+		for _, prefix := range paths {
+			if key != "*" {
+				next = append(next, append(prefix[:len(prefix):len(prefix)], key))
+				continue
+			}
+			value, _ := lookupPath(obj, prefix)
+			items, ok := value.([]interface{})
+			if !ok {
+				continue
+			}
+			for i := range items {
+				next = append(next, append(prefix[:len(prefix):len(prefix)], strconv.Itoa(i)))
+			}
+		}
+		paths = next
+	}
+	return paths
+}
+
+// setPath writes value into values at path, creating the missing arrays and objects with the shape they have in obj.
+func setPath(values subjectObj, obj subjectObj, path []string, value interface{}) {
+	var current interface{} = values
+	var source interface{} = obj
+	for i, key := range path {
+		source, _ = childValue(source, key)
+		last := i == len(path)-1
+		switch container := current.(type) {
+		case map[string]interface{}:
+			if last {
+				container[key] = value
+				return
+			}
+			next := container[key]
+			if !isContainer(next) {
+				next = emptyLike(source)
+				container[key] = next
+			}
+			current = next
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(container) {
+				return
+			}
+			if last {
+				container[index] = value
+				return
+			}
+			next := container[index]
+			if !isContainer(next) {
+				next = emptyLike(source)
+				container[index] = next
+			}
+			current = next
+		default:
+			return
+		}
+	}
+}
+
+func isContainer(value interface{}) bool {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		return true
+	}
+	return false
+}
+
+func emptyLike(source interface{}) interface{} {
+	if items, ok := source.([]interface{}); ok {
+		return make([]interface{}, len(items))
+	}
+	return make(map[string]interface{})
+}
+
+func cloneValue(value interface{}) interface{} {
+	switch val := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for key, item := range val {
+			out[key] = cloneValue(item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = cloneValue(item)
+		}
+		return out
+	}
+	return value
+}
+
+// hasPrefix reports whether one of prefixes is a prefix of path.
+func hasPrefix(path []string, prefixes [][]string) bool {
+	for _, prefix := range prefixes {
+		if len(prefix) > len(path) {
+			continue
+		}
+		match := true
+		for i, key := range prefix {
+			if path[i] != key {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package vgo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidateKeepsInput(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []string
+	}{
+		{"any parent", []string{"a", "a.n(number)"}},
+		{"any parent with transform", []string{"a(any)", "a.s(string) trim"}},
+		{"object parent", []string{"a(object)", "a.n(number)", "a.s(string) trim"}},
+		{"wildcard", []string{"items(array)", "items.*.n(number)"}},
+		{"any wildcard", []string{"items", "items.*.s(string) upper"}},
+	}
+	for _, test := range tests {
+		in := map[string]interface{}{
+			"a":     map[string]interface{}{"n": "5", "s": " x "},
+			"items": []interface{}{map[string]interface{}{"n": "7", "s": "y"}},
+		}
+		want := cloneValue(in)
+		if _, ok := Validate(in, test.rules); !ok {
+			t.Errorf("%s: validation failed", test.name)
+		}
+		if !reflect.DeepEqual(in, want) {
+			t.Errorf("%s: input changed to %v", test.name, in)
+		}
+	}
+}
+
+// failedRules maps the fields of a ValidationErrors to their rule codes.
+func failedRules(t *testing.T, err error) map[string]string {
+	if err == nil {
+		return nil
+	}
+	errs, ok := err.(ValidationErrors)
+	if !ok {
+		t.Fatalf("got %v", err)
+	}
+	out := map[string]string{}
+	for _, failure := range errs {
+		out[failure.Field] = failure.Rule
+	}
+	return out
+}
+
+func TestWildcards(t *testing.T) {
+	tests := []struct {
+		name   string
+		rules  []string
+		body   string
+		values map[string]interface{}
+		errs   map[string]string
+	}{
+		{"converted per index", []string{"items(array)", "items.*.price(number) required greaterThan(0)"},
+			`{"items": [{"price": "5"}, {"price": 2.5}]}`,
+			map[string]interface{}{"items": []interface{}{
+				map[string]interface{}{"price": 5.0}, map[string]interface{}{"price": 2.5},
+			}}, nil},
+		{"errors per index", []string{"items(array)", "items.*.price(number) required greaterThan(0)"},
+			`{"items": [{"price": 1}, {}, {"price": 0}]}`, nil,
+			map[string]string{"items.1.price": "required", "items.2.price": "number.greaterThan"}},
+		{"scalar elements", []string{"tags(array)", "tags.*(string) upper"}, `{"tags": ["a", "b"]}`,
+			map[string]interface{}{"tags": []interface{}{"A", "B"}}, nil},
+		{"nested wildcards", []string{"rows(array)", "rows.*.cells.*.n(number) lessThan(10)"},
+			`{"rows": [{"cells": [{"n": 1}]}, {"cells": [{"n": 2}, {"n": 11}]}]}`, nil,
+			map[string]string{"rows.1.cells.1.n": "number.lessThan"}},
+		{"nested write back", []string{"rows.*.cells.*.n(number)"},
+			`{"rows": [{"cells": [{"n": "1"}]}, {"cells": [{"n": "2"}, {"n": "3"}]}]}`,
+			map[string]interface{}{"rows": []interface{}{
+				map[string]interface{}{"cells": []interface{}{map[string]interface{}{"n": 1.0}}},
+				map[string]interface{}{"cells": []interface{}{map[string]interface{}{"n": 2.0}, map[string]interface{}{"n": 3.0}}},
+			}}, nil},
+		{"missing array", []string{"items.*.price(number) required"}, `{}`, map[string]interface{}{}, nil},
+		{"not an array", []string{"items.*.price(number) required"}, `{"items": {"0": {}}}`, map[string]interface{}{}, nil},
+		{"same index in conditions", []string{"items.*.type(string)", "items.*.vat(string) requiredIf(items.*.type,company)"},
+			`{"items": [{"type": "person"}, {"type": "company"}]}`, nil,
+			map[string]string{"items.1.vat": "requiredIf"}},
+	}
+	for _, test := range tests {
+		values, err := ValidateJson(test.body, test.rules)
+		if errs := failedRules(t, err); !reflect.DeepEqual(errs, test.errs) {
+			t.Errorf("%s: got errors %v, want %v", test.name, errs, test.errs)
+			continue
+		}
+		if test.errs == nil && !reflect.DeepEqual(values, test.values) {
+			t.Errorf("%s: got %v, want %v", test.name, values, test.values)
+		}
+	}
+}
+
+func TestExpandPath(t *testing.T) {
+	obj := map[string]interface{}{
+		"a": []interface{}{
+			map[string]interface{}{"b": []interface{}{1, 2}},
+			map[string]interface{}{"b": "x"},
+			map[string]interface{}{"b": []interface{}{3}},
+		},
+	}
+	tests := []struct {
+		path string
+		want [][]string
+	}{
+		{"a", [][]string{{"a"}}},
+		{"a.*", [][]string{{"a", "0"}, {"a", "1"}, {"a", "2"}}},
+		{"a.*.b.*", [][]string{{"a", "0", "b", "0"}, {"a", "0", "b", "1"}, {"a", "2", "b", "0"}}},
+		{"c.*", nil},
+	}
+	for _, test := range tests {
+		if got := expandPath(obj, splitPath(test.path)); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %v, want %v", test.path, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package vgo
+
+import "sync"
+
+// Context is the field under validation as seen by a custom rule.
+type Context struct {
+	context *phaseContext
+	obj     subjectObj
+}
+
+// Field returns the path of the field, e.g. `items.2.sku`.
+func (c *Context) Field() string {
+	return c.context.name
+}
+
+// Type returns the declared type of the field.
+func (c *Context) Type() string {
+	return c.context.typ
+}
+
+// Args returns the arguments the rule was written with.
+func (c *Context) Args() []string {
+	return c.context.args
+}
+
+// Value returns the converted value of the field.
+func (c *Context) Value() interface{} {
+	return c.context.value
+}
+
+// SetValue replaces the value that ends up in the result.
+func (c *Context) SetValue(value interface{}) {
+	c.context.value = value
+}
+
+// Lookup returns the raw input value of another field, `*` segments resolve to the indexes of the current field.
+func (c *Context) Lookup(name string) (interface{}, bool) {
+	return c.context.lookup(c.obj, name)
+}
+
+// Attribute returns the display name of a field in the active locale.
+func (c *Context) Attribute(name string) string {
+	return c.context.attribute(name)
+}
+
+// RuleFunc implements a custom rule, a non nil error fails the field.
+type RuleFunc func(ctx *Context) error
+
+// TypeCheck reports whether a raw input value is acceptable for a custom type.
+type TypeCheck func(value interface{}) bool
+
+// TypeConvert turns an accepted raw value into the value returned to the caller.
+type TypeConvert func(value interface{}) (interface{}, error)
+
+type customType struct {
+	check   TypeCheck
+	convert TypeConvert
+}
+
+var customTypes = map[string]*customType{}
+var registryMutex sync.RWMutex
+
+// RegisterRule adds rule name to type typ, or to every type when typ is empty. Typed rules are skipped for empty values.
+// On failure the message msgKey is rendered with the attribute name followed by the rule arguments,
+// when msgKey is empty the error returned by fn is used as the message.
+func RegisterRule(typ, name string, fn RuleFunc, msgKey string) {
+	rule := func(context *phaseContext, obj subjectObj) error {
+		if typ != "" && context.value == nil {
+			return nil
+		}
+		err := fn(&Context{context: context, obj: obj})
+		if err != nil {
+			context.hasError = true
+			if msgKey == "" {
+				context.err = err.Error()
+				return nil
+			}
+			args := []interface{}{context.attribute(context.name)}
+			for _, arg := range context.args {
+				args = append(args, arg)
+			}
+			context.err = context.translate(msgKey, args...)
+		}
+		return nil
+	}
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	if typ == "" {
+		sharedOperators[name] = rule
+		return
+	}
+	vld, ok := validators[typ].(map[string]validatorFunc)
+	if !ok {
+		vld = map[string]validatorFunc{}
+		validators[typ] = vld
+	}
+	vld[name] = rule
+}
+
+// RegisterType adds a field type. check rejects values with the `type.<name>` message, convert may be nil.
+func RegisterType(name string, check TypeCheck, convert TypeConvert) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	knownTypes[name] = true
+	customTypes[name] = &customType{check: check, convert: convert}
+}
+
+func (t *customType) run(context *phaseContext) bool {
+	if context.value == nil {
+		return false
+	}
+	if t.check != nil && !t.check(context.value) {
+		context.hasError = true
+		context.err = context.translate("type."+context.typ, context.attribute(context.name))
+		return false
+	}
+	if t.convert != nil {
+		value, err := t.convert(context.value)
+		if err != nil {
+			context.hasError = true
+			context.err = context.translate("type."+context.typ, context.attribute(context.name))
+			return false
+		}
+		context.value = value
+	}
+	return true
+}
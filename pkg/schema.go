@@ -0,0 +1,465 @@
+package vgo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Schema is a compiled set of rules. It is immutable once compiled and safe for concurrent use.
+type Schema struct {
+	fields  []*fieldPlan
+	options options
+}
+
+type fieldPlan struct {
+	name     string
+	path     []string
+	wildcard bool
+	typ      string
+	custom   *customType
+	rules    []*rulePlan
+	// elem is the element type of the `array<type>` shorthand, typeArgs the options of types like `bool(lenient)`.
+	elem     string
+	typeArgs []string
+	// excludes holds the excludeIf and excludeUnless rules, they run before the type conversion.
+	excludes []*rulePlan
+	// sometimes skips the field when its key is absent, bail stops at the first failing rule even when all errors
+	// are collected and omitEmpty leaves nil values out of the result.
+	sometimes bool
+	bail      bool
+	omitEmpty bool
+	// guard is the length of the path prefix that has to be present for the field to be validated.
+	guard int
+}
+
+type rulePlan struct {
+	name     string
+	args     []string
+	shared   validatorFunc
+	typed    validatorFunc
+	prepared interface{}
+}
+
+// CompileError reports a rule string that can not be compiled.
+type CompileError struct {
+	Index int
+	Field string
+	Rule  string
+	Msg   string
+}
+
+func (e *CompileError) Error() string {
+	if e.Rule == "" {
+		return fmt.Sprintf("rule %d (%s): %s", e.Index+1, e.Field, e.Msg)
+	}
+	return fmt.Sprintf("rule %d (%s): %s: %s", e.Index+1, e.Field, e.Rule, e.Msg)
+}
+
+var knownTypes = map[string]bool{
+	"any":    true,
+	"string": true,
+	"array":  true,
+	"number": true,
+	"int":    true,
+	"uint":   true,
+	"object": true,
+	"date":   true,
+	"image":  true,
+	"file":   true,
+	"bool":   true,
+}
+
+// typeOptions checks the options of the types that take some.
+var typeOptions = map[string]func(args []string) error{
+	"bool": func(args []string) error {
+		for _, arg := range args {
+			if arg != "lenient" && arg != "strict" {
+				return fmt.Errorf("unknown bool option %q", arg)
+			}
+		}
+		return nil
+	},
+	"date": checkDateOptions,
+}
+
+// Compile parses rules into a reusable Schema, opts become the defaults of every validation run by it.
+func Compile(rules []string, opts ...Option) (*Schema, error) {
+	schema := &Schema{options: options{}.with(opts)}
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	indexes := map[*fieldPlan]int{}
+	for index, rule := range rules {
+		field, err := compileRule(index, rule)
+		if err != nil {
+			return nil, err
+		}
+		schema.fields = append(schema.fields, field)
+		indexes[field] = index
+	}
+	// the `array<type>` shorthand declares its elements unless they have rules of their own
+	for i := 0; i < len(schema.fields); i++ {
+		field := schema.fields[i]
+		if field.elem == "" || schema.declares(field.name+".*") {
+			continue
+		}
+		elem, err := compileRule(indexes[field], field.name+".*("+field.elem+")")
+		if err != nil {
+			return nil, err
+		}
+		schema.fields = append(schema.fields, elem)
+		indexes[elem] = indexes[field]
+	}
+	sort.SliceStable(schema.fields, func(i, j int) bool {
+		return len(schema.fields[i].path) < len(schema.fields[j].path)
+	})
+	return schema, nil
+}
+
+func (s *Schema) declares(name string) bool {
+	for _, field := range s.fields {
+		if field.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// MustCompile is like Compile but panics if the rules can not be compiled.
+func MustCompile(rules []string, opts ...Option) *Schema {
+	schema, err := Compile(rules, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return schema
+}
+
+func compileRule(index int, rule string) (*fieldPlan, error) {
+	calls, err := parseRule(rule)
+	if err != nil {
+		err.(*SyntaxError).Index = index
+		return nil, err
+	}
+	if len(calls) == 0 {
+		return nil, &CompileError{Index: index, Msg: "empty rule"}
+	}
+	field := &fieldPlan{name: calls[0].name, path: splitPath(calls[0].name), typ: "any"}
+	field.wildcard = hasWildcard(field.path)
+	if len(calls[0].args) > 0 {
+		field.typ, field.elem = elementType(calls[0].args[0])
+	}
+	if field.elem == "" && strings.Contains(field.typ, "(") {
+		typ, err := parseRule(field.typ)
+		if err != nil || len(typ) != 1 {
+			return nil, &CompileError{Index: index, Field: field.name, Msg: fmt.Sprintf("malformed type %q", field.typ)}
+		}
+		field.typ, field.typeArgs = typ[0].name, typ[0].args
+	}
+	for _, key := range field.path {
+		if key == "" {
+			return nil, &CompileError{Index: index, Field: field.name, Msg: "malformed field path"}
+		}
+	}
+	if !knownTypes[field.typ] {
+		return nil, &CompileError{Index: index, Field: field.name, Msg: fmt.Sprintf("unknown type %q", field.typ)}
+	}
+	if len(field.typeArgs) > 0 {
+		check, ok := typeOptions[field.typ]
+		if !ok {
+			return nil, &CompileError{Index: index, Field: field.name, Msg: fmt.Sprintf("type %s takes no options", field.typ)}
+		}
+		if err := check(field.typeArgs); err != nil {
+			return nil, &CompileError{Index: index, Field: field.name, Msg: err.Error()}
+		}
+	}
+	field.custom = customTypes[field.typ]
+	for _, call := range calls[1:] {
+		if modifier, ok := fieldModifiers[call.name]; ok {
+			if len(call.args) > 0 {
+				return nil, &CompileError{Index: index, Field: field.name, Rule: call.name, Msg: fmt.Sprintf("bad argument count %d", len(call.args))}
+			}
+			modifier(field)
+			continue
+		}
+		plan, err := compileStep(field.typ, call.name, call.args)
+		if err != nil {
+			return nil, &CompileError{Index: index, Field: field.name, Rule: call.name, Msg: err.Error()}
+		}
+		if field.typ == "date" && dateArgRules[call.name] {
+			if err := checkDateArgs(call.args, contains("jalali", field.typeArgs)); err != nil {
+				return nil, &CompileError{Index: index, Field: field.name, Rule: call.name, Msg: err.Error()}
+			}
+		}
+		if excludeRules[call.name] {
+			field.excludes = append(field.excludes, plan)
+			continue
+		}
+		field.rules = append(field.rules, plan)
+	}
+	return field, nil
+}
+
+// fieldModifiers are flags written like rules that change how the chain of a field is run.
+var fieldModifiers = map[string]func(field *fieldPlan){
+	"sometimes": func(field *fieldPlan) { field.sometimes = true },
+	"bail":      func(field *fieldPlan) { field.bail = true },
+	"omitempty": func(field *fieldPlan) { field.omitEmpty = true },
+}
+
+func compileStep(typ, name string, args []string) (*rulePlan, error) {
+	plan := &rulePlan{name: name, args: args}
+	key := name
+	plan.shared = sharedOperators[name]
+	if vld, ok := validators[typ]; ok {
+		if fn, ok := vld.(map[string]validatorFunc)[name]; ok {
+			plan.typed = fn
+			key = typ + "." + name
+		}
+	}
+	if plan.typed == nil && (typ == "int" || typ == "uint") {
+		if fn, ok := integerRule(name); ok {
+			plan.typed = fn
+			key = "number." + name
+		}
+	}
+	if plan.shared == nil && plan.typed == nil {
+		return nil, fmt.Errorf("unknown rule for type %s", typ)
+	}
+	if arity, ok := ruleArity[key]; ok {
+		if len(args) < arity[0] || (arity[1] >= 0 && len(args) > arity[1]) {
+			return nil, fmt.Errorf("bad argument count %d", len(args))
+		}
+	}
+	if prepare, ok := preparers[key]; ok {
+		prepared, err := prepare(args)
+		if err != nil {
+			return nil, err
+		}
+		plan.prepared = prepared
+	}
+	return plan, nil
+}
+
+// Validate runs the compiled rules against obj. On failure the returned map holds the error messages,
+// opts override the options the schema was compiled with.
+func (s *Schema) Validate(obj map[string]interface{}, opts ...Option) (map[string]interface{}, bool) {
+	values, messages, _ := s.validate(obj, s.options.with(opts))
+	if messages != nil {
+		return messages, false
+	}
+	return values, true
+}
+
+// Check runs the compiled rules against obj and reports failures as ValidationErrors.
+func (s *Schema) Check(obj map[string]interface{}, opts ...Option) (map[string]interface{}, error) {
+	values, _, errs := s.validate(obj, s.options.with(opts))
+	if errs != nil {
+		return nil, errs
+	}
+	return values, nil
+}
+
+// ValidateJson decodes body and validates it, see the package level ValidateJson.
+func (s *Schema) ValidateJson(body string, opts ...Option) (map[string]interface{}, error) {
+	data, err := s.decodeJSON([]byte(body))
+	if err != nil {
+		return nil, ErrMalformedRequest
+	}
+	return s.validateMap(data, opts)
+}
+
+// validateMap returns the values, or the messages together with the ValidationErrors.
+func (s *Schema) validateMap(obj subjectObj, opts []Option) (map[string]interface{}, error) {
+	values, messages, errs := s.validate(obj, s.options.with(opts))
+	if errs != nil {
+		return messages, errs
+	}
+	return values, nil
+}
+
+func (s *Schema) validate(obj subjectObj, o options) (map[string]interface{}, map[string]interface{}, ValidationErrors) {
+	var values = make(map[string]interface{})
+	var messages map[string]interface{}
+	var errs ValidationErrors
+	var excluded [][]string
+	report := func(context *phaseContext) {
+		if messages == nil {
+			messages = make(map[string]interface{})
+		}
+		if o.allErrors {
+			messages[context.name] = context.errs
+		} else {
+			messages[context.name] = context.err
+		}
+		errs = append(errs, context.failures...)
+	}
+	for _, field := range s.fields {
+		paths := [][]string{field.path}
+		if field.wildcard {
+			paths = expandPath(obj, field.path)
+		}
+		for _, path := range paths {
+			if field.guard > 0 {
+				if parent, _ := lookupPath(obj, path[:field.guard]); parent == nil {
+					continue
+				}
+			}
+			if hasPrefix(path, excluded) {
+				continue
+			}
+			if field.sometimes {
+				if _, ok := lookupPath(obj, path); !ok {
+					excluded = append(excluded, path)
+					continue
+				}
+			}
+			context := field.run(s, obj, path, &o)
+			if context.excluded {
+				excluded = append(excluded, path)
+				continue
+			}
+			if context.hasError {
+				report(context)
+			} else if context.value != nil || !field.omitEmpty {
+				value := context.value
+				if field.typ != "object" && field.typ != "array" {
+					// objects and arrays are already copied, values must never share a container with obj.
+					value = cloneValue(value)
+				}
+				setPath(values, obj, path, value)
+			}
+		}
+	}
+	if o.unknown != allowUnknown {
+		for _, path := range s.unknownKeys(obj) {
+			if o.unknown == stripUnknown {
+				deletePath(values, path)
+				continue
+			}
+			context := &phaseContext{name: strings.Join(path, "."), locale: o.translator()}
+			context.err = context.translate("unknown", context.attribute(context.name))
+			value, _ := lookupPath(obj, path)
+			context.fail("unknown", nil, value)
+			report(context)
+		}
+	}
+	return values, messages, errs
+}
+
+func (f *fieldPlan) run(s *Schema, obj subjectObj, path []string, o *options) *phaseContext {
+	context := &phaseContext{
+		hasType:  true,
+		name:     f.name,
+		path:     path,
+		typ:      f.typ,
+		locale:   o.translator(),
+		schema:   s,
+		field:    f,
+		typeArgs: f.typeArgs,
+		location: o.location,
+	}
+	if f.wildcard {
+		context.name = strings.Join(path, ".")
+		for i, key := range f.path {
+			if key == "*" {
+				context.indexes = append(context.indexes, path[i])
+			}
+		}
+	}
+	for _, rule := range f.excludes {
+		context.args = rule.args
+		_ = rule.shared(context, obj)
+		if context.excluded {
+			return context
+		}
+	}
+	context.value, _ = lookupPath(obj, path)
+	raw := context.value
+	if f.typ == "file" || f.typ == "image" {
+		raw = nil
+	}
+	if f.custom != nil {
+		f.custom.run(context)
+	} else if checkInternalTypes(context) {
+		convertInternalTypes(context)
+	}
+	if context.hasError {
+		code := "type." + f.typ
+		if context.code != "" {
+			code = context.code
+		}
+		context.fail(code, nil, raw)
+		return context
+	}
+	for _, rule := range f.rules {
+		context.rule = rule.name
+		context.args = rule.args
+		context.prepared = rule.prepared
+		code := rule.name
+		if rule.shared != nil {
+			_ = rule.shared(context, obj)
+		}
+		if rule.typed != nil && !context.hasError {
+			code = f.typ + "." + rule.name
+			_ = rule.typed(context, obj)
+		}
+		if context.hasError {
+			context.fail(code, rule.args, raw)
+			if !o.allErrors || f.bail {
+				break
+			}
+			context.hasError = false
+			context.err = ""
+		}
+	}
+	if len(context.errs) > 0 {
+		context.hasError = true
+		context.err = context.errs[0]
+	}
+	return context
+}
+
+func (context *phaseContext) fail(code string, args []string, value interface{}) {
+	if context.err == "" {
+		context.err = context.translate("none", context.attribute(context.name))
+	}
+	context.errs = append(context.errs, context.err)
+	context.failures = append(context.failures, &FieldError{
+		Field:   context.name,
+		Rule:    code,
+		Args:    args,
+		Value:   value,
+		Message: context.err,
+	})
+}
+
+// maxCachedSchemas bounds the schemas compiled by the package level functions, the cache starts over once it is
+// full so rules built at runtime can not grow it forever.
+const maxCachedSchemas = 256
+
+var (
+	schemaCache      = map[string]*Schema{}
+	schemaCacheMutex sync.RWMutex
+)
+
+func cachedSchema(rules []string) (*Schema, error) {
+	key := strings.Join(rules, "\x00")
+	schemaCacheMutex.RLock()
+	schema, ok := schemaCache[key]
+	schemaCacheMutex.RUnlock()
+	if ok {
+		return schema, nil
+	}
+	schema, err := Compile(rules)
+	if err != nil {
+		return nil, err
+	}
+	schemaCacheMutex.Lock()
+	if len(schemaCache) >= maxCachedSchemas {
+		schemaCache = map[string]*Schema{}
+	}
+	schemaCache[key] = schema
+	schemaCacheMutex.Unlock()
+	return schema, nil
+}
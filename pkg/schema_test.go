@@ -0,0 +1,72 @@
+package vgo
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestSchemaCacheBounded(t *testing.T) {
+	first, err := cachedSchema([]string{"name(string) required"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := cachedSchema([]string{"name(string) required"}); again != first {
+		t.Error("schema was not reused")
+	}
+	for i := 0; i < 3*maxCachedSchemas; i++ {
+		if _, err := cachedSchema([]string{"name(string) max(" + strconv.Itoa(i) + ")"}); err != nil {
+			t.Fatal(err)
+		}
+		schemaCacheMutex.RLock()
+		size := len(schemaCache)
+		schemaCacheMutex.RUnlock()
+		if size > maxCachedSchemas {
+			t.Fatalf("cache holds %d schemas", size)
+		}
+	}
+}
+
+func TestModifiers(t *testing.T) {
+	tests := []struct {
+		name   string
+		rules  []string
+		body   string
+		values map[string]interface{}
+		errs   []string
+	}{
+		{"missing optional", []string{"nick(string)"}, `{}`, map[string]interface{}{"nick": nil}, nil},
+		{"sometimes absent", []string{"nick(string) sometimes required min(3)"}, `{}`, map[string]interface{}{}, nil},
+		{"sometimes present", []string{"nick(string) sometimes required min(3)"}, `{"nick": ""}`, nil, []string{"required", "string.min"}},
+		{"sometimes converts", []string{"n(number) sometimes"}, `{"n": "5"}`, map[string]interface{}{"n": 5.0}, nil},
+		{"sometimes parent", []string{"a(object) sometimes", "a.b(string) required"}, `{}`, map[string]interface{}{}, nil},
+		{"omitempty missing", []string{"bio(string) omitempty"}, `{}`, map[string]interface{}{}, nil},
+		{"omitempty null", []string{"bio(string) nullable omitempty"}, `{"bio": null}`, map[string]interface{}{}, nil},
+		{"omitempty empty string", []string{"bio(string) omitempty"}, `{"bio": ""}`, map[string]interface{}{"bio": ""}, nil},
+		{"all errors", []string{"code(string) required min(6) email"}, `{"code": "ab"}`, nil,
+			[]string{"string.min", "string.email"}},
+		{"bail", []string{"code(string) bail required min(6) email"}, `{"code": "ab"}`, nil, []string{"string.min"}},
+		{"bail after type", []string{"code(number) bail greaterThan(5)"}, `{"code": "x"}`, nil, []string{"type.number"}},
+	}
+	for _, test := range tests {
+		values, err := ValidateJson(test.body, test.rules, AllErrors())
+		var codes []string
+		if errs, ok := err.(ValidationErrors); ok {
+			for _, failure := range errs {
+				codes = append(codes, failure.Rule)
+			}
+		} else if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if !reflect.DeepEqual(codes, test.errs) {
+			t.Errorf("%s: got %v, want %v", test.name, codes, test.errs)
+			continue
+		}
+		if test.errs == nil && !reflect.DeepEqual(values, test.values) {
+			t.Errorf("%s: got %v, want %v", test.name, values, test.values)
+		}
+	}
+	if _, err := Compile([]string{"a(string) sometimes(1)"}); err == nil {
+		t.Error("modifier with arguments compiled")
+	}
+}
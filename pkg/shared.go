@@ -1,14 +1,12 @@
 package vgo
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
+	"time"
 )
 
-type File struct {
-	MimeType string
-	Buffer []byte
-}
-
 func checkEmptiness(obj interface{}, nullable bool) bool {
 	switch obj.(type) {
 	case nil:
@@ -32,34 +30,34 @@ var sharedOperators = map[string]validatorFunc{
 		return nil
 	},
 	"present": func(context *phaseContext, obj subjectObj) error {
-		if _, ok := obj[context.name]; !ok {
+		if _, ok := lookupPath(obj, context.path); !ok && context.value == nil {
 			context.hasError = true
-			context.err =  translate("present", translateAttribute(context.name))
+			context.err =  context.translate("present", context.attribute(context.name))
 		}
 		return nil
 	},
 	"required": func(context *phaseContext, obj subjectObj) error {
-		if val, ok := obj[context.name]; !ok || checkEmptiness(val, context.nullable) {
+		if !context.filled(obj) {
 			context.hasError = true
-			context.err = translate("required", translateAttribute(context.name))
+			context.err = context.translate("required", context.attribute(context.name))
 		}
 		return nil
 	},
 	"requiredWith": func(context *phaseContext, obj subjectObj) error {
 		allFieldsExists := true
 		for _, item := range context.args {
-			if val, ok := obj[item]; !ok || checkEmptiness(val, false) {
+			if val, ok := context.lookup(obj, item); !ok || checkEmptiness(val, false) {
 				allFieldsExists = false
 				break
 			}
 		}
 		if allFieldsExists {
-			if val, ok := obj[context.name]; !ok || checkEmptiness(val, context.nullable) {
+			if !context.filled(obj) {
 				context.hasError = true
 				if len(context.args) > 1 {
-					context.err =  translate("requiredWithAll", strings.Join(translateAttributes(context.args...), "|"), translateAttribute(context.name))
+					context.err =  context.translate("requiredWithAll", strings.Join(context.attributes(context.args...), "|"), context.attribute(context.name))
 				}else {
-					context.err =  translate("requiredWith", translateAttribute(context.args[0]), translateAttribute(context.name))
+					context.err =  context.translate("requiredWith", context.attribute(context.args[0]), context.attribute(context.name))
 				}
 			}
 		}
@@ -68,34 +66,190 @@ var sharedOperators = map[string]validatorFunc{
 	"requiredWithout": func(context *phaseContext, obj subjectObj) error {
 		allFieldsExists := true
 		for _, item := range context.args {
-			if val, ok := obj[item]; !ok || checkEmptiness(val, false) {
+			if val, ok := context.lookup(obj, item); !ok || checkEmptiness(val, false) {
 				allFieldsExists = false
 				break
 			}
 		}
 		if !allFieldsExists {
-			if val, ok := obj[context.name]; !ok || checkEmptiness(val, context.nullable) {
+			if !context.filled(obj) {
 				context.hasError = true
 				if len(context.args) > 1 {
-					context.err =  translate("requiredWithoutAll", strings.Join(translateAttributes(context.args...), "|"), translateAttribute(context.name))
+					context.err =  context.translate("requiredWithoutAll", strings.Join(context.attributes(context.args...), "|"), context.attribute(context.name))
 				}else {
-					context.err =  translate("requiredWithout", translateAttribute(context.args[0]), translateAttribute(context.name))
+					context.err =  context.translate("requiredWithout", context.attribute(context.args[0]), context.attribute(context.name))
 				}
 			}
 		}
 		return nil
 	},
+	"requiredIf": func(context *phaseContext, obj subjectObj) error {
+		if context.matches(obj) {
+			context.requireValue(obj, "requiredIf")
+		}
+		return nil
+	},
+	"requiredUnless": func(context *phaseContext, obj subjectObj) error {
+		if !context.matches(obj) {
+			context.requireValue(obj, "requiredUnless")
+		}
+		return nil
+	},
+	"prohibited": func(context *phaseContext, obj subjectObj) error {
+		if !checkEmptiness(context.value, false) {
+			context.hasError = true
+			context.err = context.translate("prohibited", context.attribute(context.name))
+		}
+		return nil
+	},
+	"prohibitedIf": func(context *phaseContext, obj subjectObj) error {
+		if context.matches(obj) {
+			context.prohibitValue(obj, "prohibitedIf")
+		}
+		return nil
+	},
+	"prohibitedUnless": func(context *phaseContext, obj subjectObj) error {
+		if !context.matches(obj) {
+			context.prohibitValue(obj, "prohibitedUnless")
+		}
+		return nil
+	},
+	"excludeIf": func(context *phaseContext, obj subjectObj) error {
+		context.excluded = context.matches(obj)
+		return nil
+	},
+	"excludeUnless": func(context *phaseContext, obj subjectObj) error {
+		context.excluded = !context.matches(obj)
+		return nil
+	},
+	"accepted": func(context *phaseContext, obj subjectObj) error {
+		if truth, ok := truthValue(context.value); !ok || !truth {
+			context.hasError = true
+			context.err = context.translate("accepted", context.attribute(context.name))
+		}
+		return nil
+	},
+	"declined": func(context *phaseContext, obj subjectObj) error {
+		if truth, ok := truthValue(context.value); !ok || truth {
+			context.hasError = true
+			context.err = context.translate("declined", context.attribute(context.name))
+		}
+		return nil
+	},
+	"default": applyDefault,
 	"confirmed": func(context *phaseContext, obj subjectObj) error {
 		arg := context.name + "Confirmation"
 		if len(context.args) > 0 {
 			arg = context.args[0]
 		}
-		a, aOk := obj[context.name]
-		b, bOk := obj[arg]
-		if !aOk || !bOk || a != b {
+		b, ok := context.other(obj, arg)
+		if !ok || context.value != b {
 			context.hasError = true
-			context.err =  translate("confirmed", translateAttribute(context.name))
+			context.err =  context.translate("confirmed", context.attribute(context.name))
 		}
 		return nil
 	},
 }
+
+// excludeRules are run before the type conversion, an excluded field is neither validated nor returned.
+var excludeRules = map[string]bool{
+	"excludeIf":     true,
+	"excludeUnless": true,
+}
+
+// matches reports whether the field named by the first argument has one of the remaining arguments as its value.
+// The other field is converted with its own type first, so `requiredIf(age,18)` matches both 18 and "18".
+func (context *phaseContext) matches(obj subjectObj) bool {
+	value := context.converted(obj, context.args[0])
+	for _, arg := range context.args[1:] {
+		if matchValue(value, arg) {
+			return true
+		}
+	}
+	return false
+}
+
+// filled reports whether the field holds a value for the required rules. The value left by the earlier rules is
+// checked, so transformers and `default` are taken into account, and a missing key counts unless a rule filled it.
+func (context *phaseContext) filled(obj subjectObj) bool {
+	if _, ok := lookupPath(obj, context.path); !ok && context.value == nil {
+		return false
+	}
+	return !checkEmptiness(context.value, context.nullable)
+}
+
+func (context *phaseContext) requireValue(obj subjectObj, key string) {
+	if !context.filled(obj) {
+		context.hasError = true
+		context.err = context.translate(key, context.attribute(context.name), context.attribute(strings.Join(context.resolve(context.args[0]), ".")), strings.Join(context.args[1:], ", "))
+	}
+}
+
+func (context *phaseContext) prohibitValue(obj subjectObj, key string) {
+	if !checkEmptiness(context.value, false) {
+		context.hasError = true
+		context.err = context.translate(key, context.attribute(context.name), context.attribute(strings.Join(context.resolve(context.args[0]), ".")), strings.Join(context.args[1:], ", "))
+	}
+}
+
+// converted returns the value of another field after the conversion of its declared type, or the raw value when the
+// field has no rule or does not convert.
+func (context *phaseContext) converted(obj subjectObj, name string) interface{} {
+	value, ok := context.lookup(obj, name)
+	if !ok || value == nil || context.schema == nil {
+		return value
+	}
+	for _, field := range context.schema.fields {
+		if field.name == name {
+			if converted, ok := convertAs(context, field, value); ok {
+				return converted
+			}
+			break
+		}
+	}
+	return value
+}
+
+// other returns the value of the field at name converted to the type of the field under validation, rules like
+// `confirmed` and `same` compare it with the current value.
+func (context *phaseContext) other(obj subjectObj, name string) (interface{}, bool) {
+	value, ok := context.lookup(obj, name)
+	if !ok || value == nil || context.field == nil {
+		return value, ok
+	}
+	if converted, ok := convertAs(context, context.field, value); ok {
+		return converted, true
+	}
+	return value, true
+}
+
+// convertAs runs the type conversion of field on value with the locale and timezone of context.
+func convertAs(context *phaseContext, field *fieldPlan, value interface{}) (interface{}, bool) {
+	other := &phaseContext{name: field.name, typ: field.typ, typeArgs: field.typeArgs, value: value}
+	other.locale, other.location = context.locale, context.location
+	if field.custom != nil {
+		field.custom.run(other)
+	} else if checkInternalTypes(other) {
+		convertInternalTypes(other)
+	}
+	return other.value, !other.hasError
+}
+
+func matchValue(value interface{}, arg string) bool {
+	switch val := value.(type) {
+	case nil:
+		return arg == "null"
+	case string:
+		return val == arg
+	case bool:
+		b, ok := coerceBool(arg)
+		return ok && b == val
+	case float64:
+		f, err := strconv.ParseFloat(arg, 64)
+		return err == nil && f == val
+	case time.Time:
+		t, err := parseDate(arg)
+		return err == nil && t.Equal(val)
+	}
+	return fmt.Sprint(value) == arg
+}
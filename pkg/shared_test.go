@@ -0,0 +1,131 @@
+package vgo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConditionalRules(t *testing.T) {
+	rules := []string{
+		"type(string)",
+		"age(number)",
+		"country(string)",
+		"company(string) requiredIf(type,company)",
+		"adult(bool) requiredIf(age,18,19)",
+		"nationalId(string) requiredUnless(country,US,UK)",
+		"coupon(string) prohibitedIf(type,guest)",
+		"referrer(string) prohibitedUnless(type,member)",
+		"internal(string) prohibited",
+		"billing(object) excludeIf(type,guest) required",
+		"billing.card(string) required",
+		"shipping(string) excludeUnless(type,company) required",
+	}
+	tests := []struct {
+		name   string
+		body   string
+		errs   map[string]string
+		absent []string
+	}{
+		{"company", `{"type": "company", "nationalId": "1", "billing": {"card": "x"}}`,
+			map[string]string{"company": "requiredIf", "shipping": "required"}, nil},
+		{"converted number", `{"age": "18", "nationalId": "1", "billing": {"card": "x"}}`,
+			map[string]string{"adult": "requiredIf"}, nil},
+		{"other number", `{"age": 20, "nationalId": "1", "billing": {"card": "x"}}`, nil, nil},
+		{"required unless", `{"country": "IR", "billing": {"card": "x"}}`, map[string]string{"nationalId": "requiredUnless"}, nil},
+		{"unless matched", `{"country": "UK", "billing": {"card": "x"}}`, nil, nil},
+		{"prohibited if", `{"type": "guest", "coupon": "x", "nationalId": "1"}`,
+			map[string]string{"coupon": "prohibitedIf"}, nil},
+		{"prohibited unless", `{"type": "guest", "referrer": "x", "nationalId": "1"}`,
+			map[string]string{"referrer": "prohibitedUnless"}, nil},
+		{"prohibited unless matched", `{"type": "member", "referrer": "x", "nationalId": "1", "billing": {"card": "x"}}`, nil, nil},
+		{"prohibited", `{"internal": "x", "nationalId": "1", "billing": {"card": "x"}}`, map[string]string{"internal": "prohibited"}, nil},
+		{"excluded", `{"type": "guest", "nationalId": "1", "billing": {"card": 5}, "shipping": 1}`, nil,
+			[]string{"billing", "shipping"}},
+		{"not excluded", `{"type": "member", "nationalId": "1", "billing": {"card": ""}}`, map[string]string{"billing.card": "required"}, nil},
+	}
+	schema := MustCompile(rules)
+	for _, test := range tests {
+		values, err := schema.ValidateJson(test.body)
+		if errs := failedRules(t, err); !reflect.DeepEqual(errs, test.errs) {
+			t.Errorf("%s: got %v, want %v", test.name, errs, test.errs)
+			continue
+		}
+		for _, field := range test.absent {
+			if _, ok := values[field]; ok {
+				t.Errorf("%s: %s was not excluded from %v", test.name, field, values)
+			}
+		}
+	}
+}
+
+func TestConditionalMessages(t *testing.T) {
+	rules := []string{"type(string)", "company(string) requiredIf(type,company,firm)"}
+	_, err := ValidateJson(`{"type": "firm"}`, rules, WithLocale(English))
+	errs, ok := err.(ValidationErrors)
+	if !ok || errs[0].Message != "The company field is required when type is company, firm." {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestLenientBool(t *testing.T) {
+	tests := []struct {
+		rule  string
+		value string
+		want  interface{}
+		code  string
+	}{
+		{"b(bool)", `true`, true, ""},
+		{"b(bool)", `"true"`, nil, "type.bool"},
+		{"b(bool)", `1`, nil, "type.bool"},
+		{"b(bool(strict))", `"yes"`, nil, "type.bool"},
+		{"b(bool(lenient))", `"true"`, true, ""},
+		{"b(bool(lenient))", `"Off"`, false, ""},
+		{"b(bool(lenient))", `1`, true, ""},
+		{"b(bool(lenient))", `0`, false, ""},
+		{"b(bool(lenient))", `2`, nil, "type.bool"},
+		{"b(bool(lenient))", `"maybe"`, nil, "type.bool"},
+		{"b(bool(lenient)) accepted", `"yes"`, true, ""},
+		{"b(bool(lenient)) accepted", `"no"`, nil, "accepted"},
+		{"b(bool(lenient)) declined", `"off"`, false, ""},
+		{"b(bool(lenient)) declined", `1`, nil, "declined"},
+		{"b(bool) accepted", `false`, nil, "accepted"},
+		{"b(bool) accepted", `null`, nil, "accepted"},
+		{"b(string) accepted", `"on"`, "on", ""},
+		{"b(number) accepted", `1`, 1.0, ""},
+		{"b(number) declined", `0`, 0.0, ""},
+		{"b(string) declined", `"yes"`, nil, "declined"},
+	}
+	for _, test := range tests {
+		values, err := ValidateJson(`{"b": `+test.value+`}`, []string{test.rule})
+		var code string
+		if errs, ok := err.(ValidationErrors); ok {
+			code = errs[0].Rule
+		} else if err != nil {
+			t.Fatalf("%s: %v", test.rule, err)
+		}
+		if code != test.code {
+			t.Errorf("%s with %s: got %q, want %q", test.rule, test.value, code, test.code)
+			continue
+		}
+		if code == "" && !reflect.DeepEqual(values["b"], test.want) {
+			t.Errorf("%s with %s: got %#v, want %#v", test.rule, test.value, values["b"], test.want)
+		}
+	}
+}
+
+func TestBoolOptionsCompile(t *testing.T) {
+	tests := []struct {
+		rule string
+		ok   bool
+	}{
+		{"b(bool(lenient))", true},
+		{"b(bool(strict)) accepted", true},
+		{"b(bool(loose))", false},
+		{"b(array<bool(lenient)>)", true},
+	}
+	for _, test := range tests {
+		if _, err := Compile([]string{test.rule}); (err == nil) != test.ok {
+			t.Errorf("%s: got %v", test.rule, err)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package vgo
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// transformString returns a string rule that replaces the value with fn(value).
+func transformString(fn func(string) string) validatorFunc {
+	return func(context *phaseContext, obj subjectObj) error {
+		if str, ok := context.value.(string); ok {
+			context.value = fn(str)
+		}
+		return nil
+	}
+}
+
+func normalizeSpace(str string) string {
+	return strings.Join(strings.Fields(str), " ")
+}
+
+func prepareDecimals(args []string) (interface{}, error) {
+	if len(args) == 0 {
+		return 0, nil
+	}
+	decimals, err := strconv.Atoi(args[0])
+	if err != nil || decimals < 0 {
+		return nil, fmt.Errorf("bad decimals %q", args[0])
+	}
+	return decimals, nil
+}
+
+// toInt truncates a number towards zero and keeps it as an int64, numbers out of the int64 range are rejected.
+func toInt(context *phaseContext, obj subjectObj) error {
+	if num, ok := context.value.(float64); ok {
+		num = math.Trunc(num)
+		if num < math.MinInt64 || num >= math.MaxInt64 {
+			context.hasError = true
+			context.err = context.translate("int.overflow", context.attribute(context.name))
+			return nil
+		}
+		context.value = int64(num)
+	}
+	return nil
+}
+
+func round(context *phaseContext, obj subjectObj) error {
+	if num, ok := context.value.(float64); ok {
+		scale := math.Pow(10, float64(context.prepared.(int)))
+		context.value = math.Round(num*scale) / scale
+	}
+	return nil
+}
+
+// applyDefault fills a missing or null value with the argument converted to the field type.
+func applyDefault(context *phaseContext, obj subjectObj) error {
+	if context.value != nil {
+		return nil
+	}
+	value, ok := convertAs(context, context.field, coerceValue(context.typ, context.args[0]))
+	if !ok {
+		context.hasError = true
+		context.err = context.translate("type."+context.typ, context.attribute(context.name))
+		return nil
+	}
+	context.value = value
+	return nil
+}
@@ -0,0 +1,94 @@
+package vgo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNumberTransforms(t *testing.T) {
+	tests := []struct {
+		rule  string
+		value string
+		want  interface{}
+		code  string
+	}{
+		{"n(number) toInt", "3.7", int64(3), ""},
+		{"n(number) toInt", "-3.7", int64(-3), ""},
+		{"n(number) toInt", "1e30", nil, "number.toInt"},
+		{"n(number) toInt greaterThan(2)", "3.2", int64(3), ""},
+		{"n(number) toInt in(1,2,3)", "3.2", int64(3), ""},
+		{"n(number) toInt digits(3)", "123.9", int64(123), ""},
+		{"n(number) toInt digits(2)", "123.9", nil, "number.digits"},
+		{"n(number) toInt digitsBetween(1,3)", "99.5", int64(99), ""},
+		{"n(number) toInt round(2)", "2.5", int64(2), ""},
+		{"n(number) round(1)", "2.25", 2.3, ""},
+		{"n(number) default(4.5) toInt", "null", int64(4), ""},
+		{"n(int) toInt", "42", int64(42), ""},
+	}
+	for _, test := range tests {
+		values, err := ValidateJson(`{"n": `+test.value+`}`, []string{test.rule})
+		var code string
+		if errs, ok := err.(ValidationErrors); ok {
+			code = errs[0].Rule
+		} else if err != nil {
+			t.Fatalf("%s: %v", test.rule, err)
+		}
+		if code != test.code {
+			t.Errorf("%s with %s: got %q, want %q", test.rule, test.value, code, test.code)
+			continue
+		}
+		if code == "" && !reflect.DeepEqual(values["n"], test.want) {
+			t.Errorf("%s with %s: got %#v, want %#v", test.rule, test.value, values["n"], test.want)
+		}
+	}
+}
+
+func TestRulesSeeTransforms(t *testing.T) {
+	tests := []struct {
+		rule string
+		body string
+		want interface{}
+		code string
+	}{
+		{"n(string) trim required", `{"n": "   "}`, nil, "required"},
+		{"n(string) trim required", `{"n": " a "}`, "a", ""},
+		{"n(number) default(5) required", `{}`, 5.0, ""},
+		{"n(number) default(5) present", `{}`, 5.0, ""},
+		{"n(number) present", `{}`, nil, "present"},
+		{"n(string) nullable required", `{}`, nil, "required"},
+		{"n(string) nullable required", `{"n": null}`, nil, ""},
+		{"n(string) trim requiredWith(k)", `{"n": " ", "k": 1}`, nil, "requiredWith"},
+		{"n(string) trim requiredWithout(k)", `{"n": " "}`, nil, "requiredWithout"},
+		{"n(string) trim requiredIf(k,1)", `{"n": " ", "k": 1}`, nil, "requiredIf"},
+		{"n(string) default(x) requiredIf(k,1)", `{"k": 1}`, "x", ""},
+		{"n(string) trim requiredUnless(k,1)", `{"n": " ", "k": 2}`, nil, "requiredUnless"},
+		{"n(string) trim prohibited", `{"n": "  "}`, "", ""},
+		{"n(string) prohibited", `{}`, nil, ""},
+		{"n(string) prohibited", `{"n": null}`, nil, ""},
+		{"n(string) prohibitedIf(k,1)", `{"k": 1}`, nil, ""},
+		{"n(string) prohibitedUnless(k,1)", `{"k": 2}`, nil, ""},
+		{"n(string) trim prohibitedIf(k,1)", `{"n": "  ", "k": 1}`, "", ""},
+		{"n(string) default(x) prohibitedUnless(k,1)", `{"k": 2}`, nil, "prohibitedUnless"},
+		{"n(string) trim same(k)", `{"n": " x", "k": "x"}`, "x", ""},
+		{"n(string) trim different(k)", `{"n": " x", "k": "x"}`, nil, "string.different"},
+		{"n(string) trim confirmed", `{"n": " x", "nConfirmation": "x"}`, "x", ""},
+		{"n(number) confirmed", `{"n": 5, "nConfirmation": "5"}`, 5.0, ""},
+		{"n(string) confirmed", `{"n": "x", "nConfirmation": "y"}`, nil, "confirmed"},
+	}
+	for _, test := range tests {
+		values, err := ValidateJson(test.body, []string{test.rule, "k", "nConfirmation"})
+		var code string
+		if errs, ok := err.(ValidationErrors); ok {
+			code = errs[0].Rule
+		} else if err != nil {
+			t.Fatalf("%s: %v", test.rule, err)
+		}
+		if code != test.code {
+			t.Errorf("%s with %s: got %q, want %q", test.rule, test.body, code, test.code)
+			continue
+		}
+		if code == "" && !reflect.DeepEqual(values["n"], test.want) {
+			t.Errorf("%s with %s: got %#v, want %#v", test.rule, test.body, values["n"], test.want)
+		}
+	}
+}
@@ -1,7 +1,6 @@
 package vgo
 
 import (
-	"fmt"
 	"time"
 )
 
@@ -18,8 +17,16 @@ var translations = map[string]string{
 	"requiredWithAll":    "در صورت وجود فیلدهای %v، فیلد %s نیز الزامی است.",
 	"requiredWithout":    "در صورت عدم وجود فیلد %v، فیلد %s الزامی است.",
 	"requiredWithoutAll": "در صورت عدم وجود فیلدهای %v، فیلد %s الزامی است.",
+	"requiredIf":         "در صورتی که %[2]s برابر %[3]v باشد، فیلد %[1]s الزامی است.",
+	"requiredUnless":     "فیلد %[1]s الزامی است، مگر آنکه %[2]s یکی از مقادیر %[3]v باشد.",
+	"prohibited":         "ارسال فیلد %s مجاز نیست.",
+	"prohibitedIf":       "در صورتی که %[2]s برابر %[3]v باشد، ارسال فیلد %[1]s مجاز نیست.",
+	"prohibitedUnless":   "ارسال فیلد %[1]s مجاز نیست، مگر آنکه %[2]s یکی از مقادیر %[3]v باشد.",
+	"accepted":           "%s باید پذیرفته شود.",
+	"declined":           "%s باید رد شود.",
 	"confirmed":          "%s با فیلد تکرار مطابقت ندارد.",
 	"none":               "فیلد %s اشتباه است.",
+	"unknown":            "فیلد %s ناشناخته است.",
 	"same":               "%s و %s باید همانند هم باشند.",
 	"different":          "%s و %s باید از یکدیگر متفاوت باشند.",
 
@@ -42,6 +49,16 @@ var translations = map[string]string{
 	"string.min":        "%s نباید کمتر از %v کاراکتر داشته باشد.",
 	"string.max":        "%s نباید بیشتر از %v کاراکتر داشته باشد.",
 	"string.between":    "%s باید بین %v و %v کاراکتر باشد.",
+
+	"string.size.graphemes":    "%s باید برابر با %v حرف باشد.",
+	"string.min.graphemes":     "%s نباید کمتر از %v حرف داشته باشد.",
+	"string.max.graphemes":     "%s نباید بیشتر از %v حرف داشته باشد.",
+	"string.between.graphemes": "%s باید بین %v و %v حرف باشد.",
+	"string.size.bytes":        "حجم %s باید برابر با %v بایت باشد.",
+	"string.min.bytes":         "حجم %s نباید کمتر از %v بایت باشد.",
+	"string.max.bytes":         "حجم %s نباید بیشتر از %v بایت باشد.",
+	"string.between.bytes":     "حجم %s باید بین %v و %v بایت باشد.",
+
 	"string.regex":      "فرمت %s معتبر نیست.",
 	"string.username":   "%s باید فقط حروف الفبا، اعداد، خط تیره و زیرخط باشد.",
 	"string.alphaNum":   "%s باید فقط حروف الفبا و اعداد باشد.",
@@ -60,15 +77,34 @@ var translations = map[string]string{
 	"number.lessThanOrEqual": "%s باید کوچکتر یا مساوی %v باشد.",
 	"number.between": "%s باید بین %v و %v باشد.",
 	"number.in":         "%s انتخاب شده، معتبر نیست.",
+	"int.overflow":      "%s خارج از محدوده مجاز است.",
 
 	"date.after": "%s(%v) باید تاریخی بعد از %v باشد.",
 	"date.before": "%s(%v) باید تاریخی قبل از %v باشد.",
 	"date.between": "%s(%v) باید تاریخی بین %v و %v باشد.",
 
+	"file.mimeMismatch": "محتوای %s با نوع اعلام شده آن مطابقت ندارد.",
+	"file.maxSize":      "حجم %s نباید بیشتر از %v باشد.",
+	"file.minSize":      "حجم %s نباید کمتر از %v باشد.",
+	"file.mimes":        "%s باید فایلی از این نوع ها باشد: %v",
+	"file.extensions":   "پسوند %s باید یکی از این موارد باشد: %v",
+
+	"image.dimensions": "ابعاد %s (%vx%v) معتبر نیست.",
+	"image.ratio":      "نسبت ابعاد %s باید %v باشد.",
+	"image.square":     "%s باید مربعی باشد.",
+
+	"array.minItems":      "%s باید حداقل %v مورد داشته باشد.",
+	"array.maxItems":      "%s نباید بیشتر از %v مورد داشته باشد.",
+	"array.sizeItems":     "%s باید %v مورد داشته باشد.",
+	"array.distinct":      "%s دارای مقادیر تکراری است.",
+	"array.containsValue": "%s باید شامل %v باشد.",
+
 	"type.string":       "فیلد %s باید رشته باشد.",
 	"type.array":        "%s باید آرایه باشد.",
 	"type.object":       "%s باید آبجکت باشد.",
 	"type.number":       "%s باید عدد یا رشته‌ای از اعداد باشد.",
+	"type.int":          "%s باید یک عدد صحیح باشد.",
+	"type.uint":         "%s باید یک عدد صحیح نامنفی باشد.",
 	"type.bool":         "فیلد %s فقط می‌تواند true و یا false باشد.",
 	"type.file":         "%s باید یک فایل معتبر باشد.",
 	"type.image":        "%s باید یک تصویر معتبر باشد.",
@@ -76,33 +112,8 @@ var translations = map[string]string{
 	"type.none":         "فیلد %s در سرور اشتباه تعریف شده است.",
 }
 
-func translate(typ string, args ...interface{}) string {
-	trs, ok := translations[typ]
-	if !ok {
-		trs = translations["none"]
-	}
-	return fmt.Sprintf(trs, args...)
-}
-
-func translateAttribute(name string) string {
-	val, ok := attributes[name]
-	if ok {
-		return val
-	}
-	return name
-}
-
 func formatDate(t time.Time) string {
-	return t.UTC().Format("2006-01-02/15:04")
-}
-func translateAttributes(name ...string) []string {
-	for i, item := range name {
-		val, ok := attributes[item]
-		if ok {
-			name[i] = val
-		}
-	}
-	return name
+	return t.Format("2006-01-02/15:04")
 }
 
 var faToEn = []rune{
@@ -0,0 +1,85 @@
+package vgo
+
+import (
+	"sort"
+	"strconv"
+)
+
+// unknownKeys returns the paths of the keys of obj that are not covered by a rule. A key is covered when a field
+// matches its path or lies below it, the content of fields without rules for their children is not inspected.
+func (s *Schema) unknownKeys(obj subjectObj) [][]string {
+	var out [][]string
+	s.walkUnknown(obj, nil, &out)
+	return out
+}
+
+func (s *Schema) walkUnknown(value interface{}, path []string, out *[][]string) {
+	switch container := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(container))
+		for key := range container {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			child := append(path[:len(path):len(path)], key)
+			if !s.covers(child) {
+				*out = append(*out, child)
+			} else if s.hasChildren(child) {
+				s.walkUnknown(container[key], child, out)
+			}
+		}
+	case []interface{}:
+		for i, item := range container {
+			child := append(path[:len(path):len(path)], strconv.Itoa(i))
+			if s.covers(child) && s.hasChildren(child) {
+				s.walkUnknown(item, child, out)
+			}
+		}
+	}
+}
+
+// covers reports whether a field path starts with path.
+func (s *Schema) covers(path []string) bool {
+	for _, field := range s.fields {
+		if len(field.path) >= len(path) && matchPattern(field.path[:len(path)], path) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasChildren reports whether a field lies strictly below path.
+func (s *Schema) hasChildren(path []string) bool {
+	for _, field := range s.fields {
+		if len(field.path) > len(path) && matchPattern(field.path[:len(path)], path) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPattern matches a field path against a concrete path, `*` matches array indexes.
+func matchPattern(pattern []string, path []string) bool {
+	for i, key := range pattern {
+		if key == path[i] {
+			continue
+		}
+		if _, err := strconv.Atoi(path[i]); key != "*" || err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// deletePath removes the key at path from the map that holds it. values never shares a container with the input,
+// so stripping a key does not change the caller's data.
+func deletePath(values map[string]interface{}, path []string) {
+	parent, ok := lookupPath(values, path[:len(path)-1])
+	if !ok {
+		return
+	}
+	if obj, ok := parent.(map[string]interface{}); ok {
+		delete(obj, path[len(path)-1])
+	}
+}
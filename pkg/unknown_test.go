@@ -0,0 +1,109 @@
+package vgo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnknownKeys(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []string
+		body  string
+		want  [][]string
+	}{
+		{"none", []string{"a(string)"}, `{"a": "x"}`, nil},
+		{"root", []string{"a(string)"}, `{"a": "x", "b": 1, "c": 2}`, [][]string{{"b"}, {"c"}}},
+		{"nested", []string{"a(object)", "a.b(string)"}, `{"a": {"b": "x", "c": 1}}`, [][]string{{"a", "c"}}},
+		{"object without children", []string{"a(object)"}, `{"a": {"b": "x", "c": 1}}`, nil},
+		{"wildcard", []string{"items(array)", "items.*.id(int)"}, `{"items": [{"id": 1}, {"id": 2, "x": 3}]}`,
+			[][]string{{"items", "1", "x"}}},
+		{"array without children", []string{"tags(array<string>)"}, `{"tags": ["a", "b"]}`, nil},
+		{"parent covered by child only", []string{"a.b(string)"}, `{"a": {"b": "x", "c": 1}, "d": 2}`,
+			[][]string{{"a", "c"}, {"d"}}},
+	}
+	for _, test := range tests {
+		schema := MustCompile(test.rules)
+		obj, err := schema.decodeJSON([]byte(test.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := schema.unknownKeys(obj); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestUnknownModes(t *testing.T) {
+	rules := []string{"a(object)", "a.b(string) trim", "items(array)", "items.*.id(int)"}
+	body := `{"a": {"b": " x ", "c": 1}, "items": [{"id": 1, "x": 2}], "d": 3}`
+	values, err := MustCompile(rules, StripUnknown()).ValidateJson(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"a":     map[string]interface{}{"b": "x"},
+		"items": []interface{}{map[string]interface{}{"id": int64(1)}},
+	}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("strip: got %v, want %v", values, want)
+	}
+	_, err = MustCompile(rules, RejectUnknown()).ValidateJson(body)
+	errs, _ := err.(ValidationErrors)
+	var fields []string
+	for _, failure := range errs {
+		if failure.Rule == "unknown" {
+			fields = append(fields, failure.Field)
+		}
+	}
+	if want := []string{"a.c", "d", "items.0.x"}; !reflect.DeepEqual(fields, want) {
+		t.Errorf("reject: got %v, want %v", fields, want)
+	}
+}
+
+func TestStripUnknownKeepsInput(t *testing.T) {
+	in := map[string]interface{}{"a": map[string]interface{}{"b": " x ", "c": "y"}}
+	want := cloneValue(in)
+	values, ok := Validate(in, []string{"a(any)", "a.b(string) trim"}, StripUnknown())
+	if !ok {
+		t.Fatalf("got %v", values)
+	}
+	if !reflect.DeepEqual(in, want) {
+		t.Errorf("input changed to %v", in)
+	}
+	if got := values["a"]; !reflect.DeepEqual(got, map[string]interface{}{"b": "x"}) {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestUnknownOptions(t *testing.T) {
+	rules := []string{"name(string)"}
+	body := `{"name": "x", "nmae": "y"}`
+	tests := []struct {
+		name   string
+		schema []Option
+		call   []Option
+		values map[string]interface{}
+		errs   map[string]string
+	}{
+		{"default", nil, nil, map[string]interface{}{"name": "x"}, nil},
+		{"schema reject", []Option{RejectUnknown()}, nil, nil, map[string]string{"nmae": "unknown"}},
+		{"call reject", nil, []Option{RejectUnknown()}, nil, map[string]string{"nmae": "unknown"}},
+		{"call allows", []Option{RejectUnknown()}, []Option{AllowUnknown()}, map[string]interface{}{"name": "x"}, nil},
+		{"call strips", []Option{RejectUnknown()}, []Option{StripUnknown()}, map[string]interface{}{"name": "x"}, nil},
+	}
+	for _, test := range tests {
+		values, err := MustCompile(rules, test.schema...).ValidateJson(body, test.call...)
+		if errs := failedRules(t, err); !reflect.DeepEqual(errs, test.errs) {
+			t.Errorf("%s: got %v, want %v", test.name, errs, test.errs)
+			continue
+		}
+		if test.errs == nil && !reflect.DeepEqual(values, test.values) {
+			t.Errorf("%s: got %v, want %v", test.name, values, test.values)
+		}
+	}
+	_, err := ValidateJson(body, rules, RejectUnknown(), WithLocale(English))
+	if errs, ok := err.(ValidationErrors); !ok || errs[0].Message != "The nmae field is not allowed." {
+		t.Errorf("got %v", err)
+	}
+}
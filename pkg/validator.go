@@ -1,10 +1,11 @@
 package vgo
 
 import (
-	"encoding/base64"
 	"encoding/json"
-	"errors"
+	"mime/multipart"
 	"reflect"
+	"strings"
+	"time"
 )
 
 type subjectObj = map[string]interface{}
@@ -18,11 +19,43 @@ type phaseContext struct {
 	rule     string
 	value    interface{}
 	err      string
+	errs     []string
+	failures []*FieldError
+	locale   Translator
 	args     []string
 	hasError bool
 	nullable bool
-	mime     string
 	required bool
+	prepared interface{}
+	path     []string
+	indexes  []string
+	// code replaces the `type.<typ>` error code of conversion failures.
+	code string
+	// schema is the schema being run, conditional rules use it to convert the fields they depend on.
+	schema   *Schema
+	field    *fieldPlan
+	typeArgs []string
+	location *time.Location
+	excluded bool
+}
+
+func (context *phaseContext) hasTypeArg(name string) bool {
+	for _, arg := range context.typeArgs {
+		if arg == name {
+			return true
+		}
+	}
+	return false
+}
+
+// typeOption returns the value of a `key=value` type option.
+func (context *phaseContext) typeOption(key string) string {
+	for _, arg := range context.typeArgs {
+		if strings.HasPrefix(arg, key+"=") {
+			return arg[len(key)+1:]
+		}
+	}
+	return ""
 }
 
 func checkInternalTypes(context *phaseContext) bool {
@@ -30,17 +63,19 @@ func checkInternalTypes(context *phaseContext) bool {
 		return false
 	}
 	switch context.typ {
+	case "any":
+		break
 	case "string":
 		if reflect.TypeOf(context.value).Kind() != reflect.String {
 			context.hasError = true
-			context.err = translate("type.string", translateAttribute(context.name))
+			context.err = context.translate("type.string", context.attribute(context.name))
 			return false
 		}
 		break
 	case "array":
 		if reflect.TypeOf(context.value).Kind() != reflect.Slice {
 			context.hasError = true
-			context.err = translate("type.array", translateAttribute(context.name))
+			context.err = context.translate("type.array", context.attribute(context.name))
 			return false
 		}
 		break
@@ -51,48 +86,55 @@ func checkInternalTypes(context *phaseContext) bool {
 			kind == reflect.Uint || kind == reflect.Uint8 || kind == reflect.Uint16 || kind == reflect.Uint32 || kind == reflect.Uint64 ||
 			kind == reflect.Float32 || kind == reflect.Float64) {
 			context.hasError = true
-			context.err = translate("type.number", translateAttribute(context.name))
+			context.err = context.translate("type.number", context.attribute(context.name))
+			return false
+		}
+		break
+	case "int", "uint":
+		if _, ok := parseInteger(context.value); !ok && reflect.TypeOf(context.value).Kind() != reflect.String {
+			context.hasError = true
+			context.err = context.translate("type."+context.typ, context.attribute(context.name))
 			return false
 		}
 		break
 	case "object":
-		if reflect.TypeOf(context.value).Kind() != reflect.Interface {
+		if reflect.TypeOf(context.value).Kind() != reflect.Map {
 			context.hasError = true
-			context.err = translate("type.object", translateAttribute(context.name))
+			context.err = context.translate("type.object", context.attribute(context.name))
 			return false
 		}
 		break
 	case "date":
 		if reflect.TypeOf(context.value).Kind() != reflect.String {
 			context.hasError = true
-			context.err = translate("type.date", translateAttribute(context.name))
+			context.err = context.translate("type.date", context.attribute(context.name))
 			return false
 		}
 		break
 	case "image":
-		if reflect.TypeOf(context.value).Kind() != reflect.String {
+		if _, ok := context.value.(*multipart.FileHeader); !ok && reflect.TypeOf(context.value).Kind() != reflect.String {
 			context.hasError = true
-			context.err = translate("type.image", translateAttribute(context.name))
+			context.err = context.translate("type.image", context.attribute(context.name))
 			return false
 		}
 		break
 	case "file":
-		if reflect.TypeOf(context.value).Kind() != reflect.String {
+		if _, ok := context.value.(*multipart.FileHeader); !ok && reflect.TypeOf(context.value).Kind() != reflect.String {
 			context.hasError = true
-			context.err = translate("type.file", translateAttribute(context.name))
+			context.err = context.translate("type.file", context.attribute(context.name))
 			return false
 		}
 		break
 	case "bool":
-		if reflect.TypeOf(context.value).Kind() != reflect.Bool {
+		if reflect.TypeOf(context.value).Kind() != reflect.Bool && !context.hasTypeArg("lenient") {
 			context.hasError = true
-			context.err = translate("type.bool", translateAttribute(context.name))
+			context.err = context.translate("type.bool", context.attribute(context.name))
 			return false
 		}
 		break
 	default:
 		context.hasError = true
-		context.err = translate("type.none", translateAttribute(context.name))
+		context.err = context.translate("type.none", context.attribute(context.name))
 		return false
 	}
 	return true
@@ -113,6 +155,10 @@ func convertInternalTypes(context *phaseContext) bool {
 			i := reflect.Indirect(reflect.ValueOf(context.value))
 			context.value = i.Convert(reflect.TypeOf(float64(0))).Float()
 			return true
+		} else if num, ok := context.value.(json.Number); ok {
+			var err error
+			context.value, err = num.Float64()
+			strict = err == nil
 		} else if kind == reflect.String {
 			context.value, strict = convertToNumber(context.value.(string))
 		} else {
@@ -120,93 +166,54 @@ func convertInternalTypes(context *phaseContext) bool {
 		}
 		if !strict {
 			context.hasError = true
-			context.err = translate("type.number", translateAttribute(context.name))
+			context.err = context.translate("type.number", context.attribute(context.name))
 			return false
 		}
 		break
+	case "int", "uint":
+		return convertInteger(context)
 	case "object":
-		if reflect.TypeOf(context.value).Kind() != reflect.Interface {
+		obj, ok := context.value.(map[string]interface{})
+		if !ok {
 			context.hasError = true
-			context.err = translate("type.object", translateAttribute(context.name))
+			context.err = context.translate("type.object", context.attribute(context.name))
 			return false
 		}
+		context.value = cloneValue(obj)
 		break
-	case "date":
-		if reflect.TypeOf(context.value).Kind() != reflect.String {
-			context.hasError = true
-			context.err = translate("type.date", translateAttribute(context.name))
-			return false
-		}
-		tm, err := parseDate(context.value.(string))
-		if err != nil {
+	case "array":
+		if reflect.TypeOf(context.value).Kind() != reflect.Slice {
 			context.hasError = true
-			context.err = translate("type.date", translateAttribute(context.name))
+			context.err = context.translate("type.array", context.attribute(context.name))
 			return false
 		}
-		context.value = tm
+		context.value = cloneValue(context.value)
 		break
-	case "image":
+	case "date":
 		if reflect.TypeOf(context.value).Kind() != reflect.String {
 			context.hasError = true
-			context.err = translate("type.image", translateAttribute(context.name))
+			context.err = context.translate("type.date", context.attribute(context.name))
 			return false
 		}
-		val := context.value.(string)
-		start := 0
-		if val[0:5] == "data:" {
-			start = 5
-			for i, char := range val {
-				if char == ';' {
-					context.mime = val[start:i]
-					start = i + 8
-					break
-				}
-			}
-		}
-		data, err := base64.StdEncoding.DecodeString(val[start:])
+		tm, err := context.parseDateValue(context.value.(string))
 		if err != nil {
 			context.hasError = true
-			context.err = translate("type.image", translateAttribute(context.name))
+			context.err = context.translate("type.date", context.attribute(context.name))
 			return false
 		}
-		context.value = &File{
-			MimeType: context.mime,
-			Buffer:   data,
-		}
+		context.value = tm.In(context.timezone())
 		break
+	case "image":
+		return decodeFile(context) && decodeImage(context)
 	case "file":
-		if reflect.TypeOf(context.value).Kind() != reflect.String {
-			context.hasError = true
-			context.err = translate("type.file", translateAttribute(context.name))
-			return false
-		}
-		val := context.value.(string)
-		start := 0
-		if val[0:5] == "data:" {
-			start = 5
-			for i, char := range val {
-				if char == ';' {
-					context.mime = val[start:i]
-					start = i + 8
-					break
-				}
-			}
-		}
-		data, err := base64.StdEncoding.DecodeString(val[start:])
-		if err != nil {
-			context.hasError = true
-			context.err = translate("type.file", translateAttribute(context.name))
-			return false
-		}
-		context.value = &File{
-			MimeType: context.mime,
-			Buffer:   data,
-		}
-		break
+		return decodeFile(context)
 	case "bool":
+		if b, ok := truthValue(context.value); ok && context.hasTypeArg("lenient") {
+			context.value = b
+		}
 		if reflect.TypeOf(context.value).Kind() != reflect.Bool {
 			context.hasError = true
-			context.err = translate("type.bool", translateAttribute(context.name))
+			context.err = context.translate("type.bool", context.attribute(context.name))
 			return false
 		}
 		break
@@ -214,79 +221,41 @@ func convertInternalTypes(context *phaseContext) bool {
 	return true
 }
 
-func ValidateJson(body string, rules []string) (map[string]interface{}, error) {
-	var data map[string]interface{}
-	err := json.Unmarshal([]byte(body), &data)
+// ValidateJson decodes body and validates it. When validation fails the returned map holds the error messages
+// and the error is a ValidationErrors.
+func ValidateJson(body string, rules []string, opts ...Option) (map[string]interface{}, error) {
+	schema, messages, err := schemaFor(rules, opts)
 	if err != nil {
-		return nil, errors.New("malformed request")
-	}
-	value, pass := validate(data, rules)
-	if pass {
-		return value, nil
-	}else {
-		return value, errors.New("validation failed")
+		return messages, err
 	}
+	return schema.ValidateJson(body, opts...)
 }
 
-func Validate(body map[string]interface{}, rules []string) (map[string]interface{}, bool) {
-	return validate(body, rules)
+func Validate(body map[string]interface{}, rules []string, opts ...Option) (map[string]interface{}, bool) {
+	value, err := validate(body, rules, opts)
+	return value, err == nil
 }
-func validate(obj subjectObj, rules []string) (map[string]interface{}, bool) {
-	var values = make(map[string]interface{})
-	var errors = make(map[string]interface{})
-	err := false
-	for _, rule := range rules {
-		context := &phaseContext{
-			typ: "any",
-		}
-		evalRuleChain(rule, func(name string, args ...string) bool {
-			context.args = args
-			if !context.hasType {
-				context.name = name
-				if len(args) > 0 {
-					context.typ = args[0]
-				}
-				context.hasType = true
-				context.value = obj[name]
-				checkInternalTypes(context)
-				convertInternalTypes(context)
-				return true
-			}
-			context.rule = name
-			fn, ok := sharedOperators[context.rule]
-			if ok {
-				_ = fn(context, obj)
-				if context.hasError {
-					return false
-				}
-			}
-			//if !checkInternalTypes(context) {
-			//	return false
-			//}
-			vld, ok := validators[context.typ]
-			if ok {
-				fn, ok = vld.(map[string]validatorFunc)[context.rule]
-				if ok {
-					_ = fn(context, obj)
-				}
-			}
-			if context.hasError {
-				if context.err == "" {
-					context.err = translate("none", translateAttribute(context.name))
-				}
-				return false
-			}
-			return true
-		})
-		if context.hasError {
-			errors[context.name] = context.err
-			err = true
-		} else {
-			values[context.name] = context.value
-		}
+
+func validate(obj subjectObj, rules []string, opts []Option) (map[string]interface{}, error) {
+	schema, messages, err := schemaFor(rules, opts)
+	if err != nil {
+		return messages, err
 	}
-	if err {
-		return errors, false
+	return schema.validateMap(obj, opts)
+}
+
+// schemaFor compiles rules through the cache, a compile error is also reported as a message of the failing field.
+func schemaFor(rules []string, opts []Option) (*Schema, map[string]interface{}, error) {
+	schema, err := cachedSchema(rules)
+	if err != nil {
+		name := ""
+		if compileErr, ok := err.(*CompileError); ok {
+			name = compileErr.Field
+		}
+		translator := options{}.with(opts).translator()
+		return nil, map[string]interface{}{
+			name: translator.Translate("type.none", translator.Attribute(name)),
+		}, err
 	}
-	return values, true
-}
\ No newline at end of file
+	return schema, nil, nil
+}
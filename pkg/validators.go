@@ -19,6 +19,136 @@ var alphaNumeric = regexp.MustCompile("^[a-zA-Z0-9\\s.\\-]+$")
 var persian = regexp.MustCompile("^[\u0600-\u06FF\\s]+$")
 var alphaPersian = regexp.MustCompile("^[a-zA-Z0-9\u0600-\u06FF\\s]+$")
 var alpha = regexp.MustCompile("^[a-zA-Z0-9\\s]+$")
+var mobile = regexp.MustCompile("^[0][9][0-9]{9}$")
+var phone = regexp.MustCompile("^[0][1-8][0-9]{9}$")
+
+func prepareRegex(args []string) (interface{}, error) {
+	return regexp.Compile(args[0])
+}
+
+func prepareNumbers(args []string) (interface{}, error) {
+	for _, arg := range args {
+		if _, err := strconv.ParseFloat(arg, 64); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+var preparers = map[string]func(args []string) (interface{}, error){
+	"string.regex":              prepareRegex,
+	"string.notRegex":           prepareRegex,
+	"string.size":               prepareLength(1),
+	"string.min":                prepareLength(1),
+	"string.max":                prepareLength(1),
+	"string.between":            prepareLength(2),
+	"number.in":                 prepareNumbers,
+	"number.digits":             prepareNumbers,
+	"number.digitsBetween":      prepareNumbers,
+	"number.greaterThan":        prepareNumbers,
+	"number.greaterThanOrEqual": prepareNumbers,
+	"number.lessThan":           prepareNumbers,
+	"number.lessThanOrEqual":    prepareNumbers,
+	"number.between":            prepareNumbers,
+	"number.round":              prepareDecimals,
+	"file.maxSize":              prepareSize,
+	"file.minSize":              prepareSize,
+	"image.maxSize":             prepareSize,
+	"image.minSize":             prepareSize,
+	"image.dimensions":          prepareDimensions,
+	"image.ratio":               prepareRatio,
+	"array.minItems":            prepareCount,
+	"array.maxItems":            prepareCount,
+	"array.sizeItems":           prepareCount,
+}
+
+// ruleArity holds the minimum and maximum argument count of each rule, -1 means unbounded.
+var ruleArity = map[string][2]int{
+	"nullable":         {0, 0},
+	"present":          {0, 0},
+	"required":         {0, 0},
+	"requiredWith":     {1, -1},
+	"requiredWithout":  {1, -1},
+	"requiredIf":       {2, -1},
+	"requiredUnless":   {2, -1},
+	"prohibited":       {0, 0},
+	"prohibitedIf":     {2, -1},
+	"prohibitedUnless": {2, -1},
+	"excludeIf":        {2, -1},
+	"excludeUnless":    {2, -1},
+	"confirmed":        {0, 1},
+	"default":          {1, 1},
+	"accepted":         {0, 0},
+	"declined":         {0, 0},
+
+	"date.after":   {1, 1},
+	"date.before":  {1, 1},
+	"date.between": {2, 2},
+
+	"file.maxSize":     {1, 1},
+	"file.minSize":     {1, 1},
+	"file.mimes":       {1, -1},
+	"file.extensions":  {1, -1},
+	"image.maxSize":    {1, 1},
+	"image.minSize":    {1, 1},
+	"image.mimes":      {1, -1},
+	"image.extensions": {1, -1},
+	"image.dimensions": {1, -1},
+	"image.ratio":      {1, 1},
+	"image.square":     {0, 0},
+
+	"array.minItems":      {1, 1},
+	"array.maxItems":      {1, 1},
+	"array.sizeItems":     {1, 1},
+	"array.distinct":      {0, 1},
+	"array.containsValue": {1, -1},
+
+	"number.in":                 {1, -1},
+	"number.digits":             {1, 1},
+	"number.digitsBetween":      {2, 2},
+	"number.integer":            {0, 0},
+	"number.greaterThan":        {1, 1},
+	"number.greaterThanOrEqual": {1, 1},
+	"number.lessThan":           {1, 1},
+	"number.lessThanOrEqual":    {1, 1},
+	"number.between":            {2, 2},
+	"number.toInt":              {0, 0},
+	"number.round":              {0, 1},
+
+	"string.national":   {0, 0},
+	"string.filled":     {0, 0},
+	"string.json":       {0, 0},
+	"string.url":        {0, 0},
+	"string.uuid":       {0, 0},
+	"string.ip":         {0, 0},
+	"string.ipv4":       {0, 0},
+	"string.ipv6":       {0, 0},
+	"string.email":      {0, 0},
+	"string.mobile":     {0, 0},
+	"string.phone":      {0, 0},
+	"string.in":         {1, -1},
+	"string.inArray":    {1, 1},
+	"string.notIn":      {1, -1},
+	"string.size":       {1, 2},
+	"string.min":        {1, 2},
+	"string.max":        {1, 2},
+	"string.between":    {2, 3},
+	"string.username":   {0, 0},
+	"string.alphaNum":   {0, 0},
+	"string.alpha":      {0, 2},
+	"string.regex":      {1, 1},
+	"string.notRegex":   {1, 1},
+	"string.contains":   {1, -1},
+	"string.startsWith": {1, -1},
+	"string.endsWith":   {1, -1},
+	"string.same":       {1, 1},
+	"string.different":  {1, 1},
+
+	"string.trim":           {0, 0},
+	"string.lower":          {0, 0},
+	"string.upper":          {0, 0},
+	"string.normalizeSpace": {0, 0},
+}
 
 func contains(val string, args []string) bool {
 	for _, item := range args {
@@ -30,16 +160,19 @@ func contains(val string, args []string) bool {
 }
 
 var validators = map[string]interface{}{
+	"file":  fileValidators(),
+	"image": imageValidators(),
+	"array": arrayValidators(),
 	"date": map[string]validatorFunc{
 		"after": func(context *phaseContext, obj subjectObj) error {
 			if context.value == nil{
 				return nil
 			}
-			a, _ := parseDate(context.args[0])
+			a, ok := context.dateArg(obj, context.args[0])
 			v := context.value.(time.Time)
-			if !v.After(a) {
+			if ok && !v.After(a) {
 				context.hasError = true
-				context.err = translate("date.after", translateAttribute(context.name), formatDate(v), formatDate(a))
+				context.err = context.translate("date.after", context.attribute(context.name), context.formatDate(v), context.formatDate(a))
 			}
 			return nil
 		},
@@ -47,11 +180,11 @@ var validators = map[string]interface{}{
 			if context.value == nil{
 				return nil
 			}
-			a, _ := parseDate(context.args[0])
+			a, ok := context.dateArg(obj, context.args[0])
 			v := context.value.(time.Time)
-			if !v.Before(a) {
+			if ok && !v.Before(a) {
 				context.hasError = true
-				context.err = translate("date.before", translateAttribute(context.name), formatDate(v), formatDate(a))
+				context.err = context.translate("date.before", context.attribute(context.name), context.formatDate(v), context.formatDate(a))
 			}
 			return nil
 		},
@@ -59,17 +192,19 @@ var validators = map[string]interface{}{
 			if context.value == nil{
 				return nil
 			}
-			a, _ := parseDate(context.args[0])
-			b, _ := parseDate(context.args[1])
+			a, aOk := context.dateArg(obj, context.args[0])
+			b, bOk := context.dateArg(obj, context.args[1])
 			v := context.value.(time.Time)
-			if v.Before(a) || v.After(b) {
+			if aOk && bOk && (v.Before(a) || v.After(b)) {
 				context.hasError = true
-				context.err = translate("date.between", translateAttribute(context.name), formatDate(v), formatDate(a), formatDate(b))
+				context.err = context.translate("date.between", context.attribute(context.name), context.formatDate(v), context.formatDate(a), context.formatDate(b))
 			}
 			return nil
 		},
 	},
 	"number": map[string]validatorFunc{
+		"toInt": toInt,
+		"round": round,
 		"in": func(context *phaseContext, obj subjectObj) error {
 			if context.value == nil{
 				return nil
@@ -83,7 +218,7 @@ var validators = map[string]interface{}{
 				}
 			}
 			context.hasError = true
-			context.err = translate("number.in", translateAttribute(context.name))
+			context.err = context.translate("number.in", context.attribute(context.name))
 			return nil
 		},
 		"digits": func(context *phaseContext, obj subjectObj) error {
@@ -91,14 +226,15 @@ var validators = map[string]interface{}{
 				return nil
 			}
 			a, _ := strconv.Atoi(context.args[0])
-			v := (int64)(math.Floor(context.value.(float64)))
+			i := reflect.Indirect(reflect.ValueOf(context.value))
+			v := (int64)(math.Floor(i.Convert(reflect.TypeOf(float64(0))).Float()))
 			k:=1
 			for i := v; i > 10; i/=10 {
 				k++
 			}
 			if a != k {
 				context.hasError = true
-				context.err = translate("number.digits", translateAttribute(context.name), a)
+				context.err = context.translate("number.digits", context.attribute(context.name), a)
 			}
 			return nil
 		},
@@ -108,14 +244,15 @@ var validators = map[string]interface{}{
 			}
 			a, _ := strconv.Atoi(context.args[0])
 			b, _ := strconv.Atoi(context.args[1])
-			v := (int64)(math.Floor(context.value.(float64)))
+			i := reflect.Indirect(reflect.ValueOf(context.value))
+			v := (int64)(math.Floor(i.Convert(reflect.TypeOf(float64(0))).Float()))
 			k:=1
 			for i := v; i > 10; i/=10 {
 				k++
 			}
 			if k < a || k > b {
 				context.hasError = true
-				context.err = translate("number.digitsBetween", translateAttribute(context.name), a, b)
+				context.err = context.translate("number.digitsBetween", context.attribute(context.name), a, b)
 			}
 			return nil
 		},
@@ -139,7 +276,7 @@ var validators = map[string]interface{}{
 			val := i.Convert(reflect.TypeOf(float64(0))).Float()
 			if val <= a {
 				context.hasError = true
-				context.err = translate("number.greaterThan", translateAttribute(context.name), a)
+				context.err = context.translate("number.greaterThan", context.attribute(context.name), a)
 			}
 			return nil
 		},
@@ -152,7 +289,7 @@ var validators = map[string]interface{}{
 			val := i.Convert(reflect.TypeOf(float64(0))).Float()
 			if val < a {
 				context.hasError = true
-				context.err = translate("number.greaterThanOrEqual", translateAttribute(context.name), a)
+				context.err = context.translate("number.greaterThanOrEqual", context.attribute(context.name), a)
 			}
 			return nil
 		},
@@ -165,7 +302,7 @@ var validators = map[string]interface{}{
 			val := i.Convert(reflect.TypeOf(float64(0))).Float()
 			if val >= a {
 				context.hasError = true
-				context.err = translate("number.lessThan", translateAttribute(context.name), a)
+				context.err = context.translate("number.lessThan", context.attribute(context.name), a)
 			}
 			return nil
 		},
@@ -178,7 +315,7 @@ var validators = map[string]interface{}{
 			val := i.Convert(reflect.TypeOf(float64(0))).Float()
 			if val > a {
 				context.hasError = true
-				context.err = translate("number.lessThanOrEqual", translateAttribute(context.name), a)
+				context.err = context.translate("number.lessThanOrEqual", context.attribute(context.name), a)
 			}
 			return nil
 		},
@@ -192,12 +329,16 @@ var validators = map[string]interface{}{
 			val := i.Convert(reflect.TypeOf(float64(0))).Float()
 			if val < a || val > b {
 				context.hasError = true
-				context.err = translate("number.between", translateAttribute(context.name), a, b)
+				context.err = context.translate("number.between", context.attribute(context.name), a, b)
 			}
 			return nil
 		},
 	},
 	"string": map[string]validatorFunc{
+		"trim":           transformString(strings.TrimSpace),
+		"lower":          transformString(strings.ToLower),
+		"upper":          transformString(strings.ToUpper),
+		"normalizeSpace": transformString(normalizeSpace),
 		"national": func(context *phaseContext, obj subjectObj) error {
 			if context.value == nil{
 				return nil
@@ -205,7 +346,7 @@ var validators = map[string]interface{}{
 			str := context.value.(string)
 			if !isValidIranianNationalCode(str) {
 				context.hasError = true
-				context.err = translate("string.nationalCode", translateAttribute(context.name))
+				context.err = context.translate("string.national", context.attribute(context.name))
 			}
 			return nil
 		},
@@ -216,7 +357,7 @@ var validators = map[string]interface{}{
 			str := context.value.(string)
 			if len(str) < 1 {
 				context.hasError = true
-				context.err = translate("string.filled", translateAttribute(context.name))
+				context.err = context.translate("string.filled", context.attribute(context.name))
 			}
 			return nil
 		},
@@ -229,7 +370,7 @@ var validators = map[string]interface{}{
 			err := json.Unmarshal([]byte(str), &data)
 			if err != nil {
 				context.hasError = true
-				context.err = translate("string.json", translateAttribute(context.name))
+				context.err = context.translate("string.json", context.attribute(context.name))
 			}
 			return nil
 		},
@@ -240,7 +381,7 @@ var validators = map[string]interface{}{
 			_, err := url.ParseRequestURI(context.value.(string))
 			if err != nil {
 				context.hasError = true
-				context.err = translate("string.url", translateAttribute(context.name))
+				context.err = context.translate("string.url", context.attribute(context.name))
 			}
 			return nil
 		},
@@ -251,7 +392,7 @@ var validators = map[string]interface{}{
 			_, err := uuid.Parse(context.value.(string))
 			if err != nil {
 				context.hasError = true
-				context.err = translate("string.uuid", translateAttribute(context.name))
+				context.err = context.translate("string.uuid", context.attribute(context.name))
 			}
 			return nil
 		},
@@ -262,7 +403,7 @@ var validators = map[string]interface{}{
 			test := net.ParseIP(context.value.(string))
 			if test.To4() == nil || test.To16() == nil {
 				context.hasError = true
-				context.err = translate("string.ip", translateAttribute(context.name))
+				context.err = context.translate("string.ip", context.attribute(context.name))
 			}
 			return nil
 		},
@@ -273,7 +414,7 @@ var validators = map[string]interface{}{
 			test := net.ParseIP(context.value.(string))
 			if test.To4() == nil {
 				context.hasError = true
-				context.err = translate("string.ipv4", translateAttribute(context.name))
+				context.err = context.translate("string.ipv4", context.attribute(context.name))
 			}
 			return nil
 		},
@@ -284,7 +425,7 @@ var validators = map[string]interface{}{
 			test := net.ParseIP(context.value.(string))
 			if test.To16() == nil {
 				context.hasError = true
-				context.err = translate("string.ipv6", translateAttribute(context.name))
+				context.err = context.translate("string.ipv6", context.attribute(context.name))
 			}
 			return nil
 		},
@@ -294,7 +435,7 @@ var validators = map[string]interface{}{
 			}
 			if !emailValidator.MatchString(context.value.(string)) {
 				context.hasError = true
-				context.err = translate("string.email", translateAttribute(context.name))
+				context.err = context.translate("string.email", context.attribute(context.name))
 			}
 			return nil
 		},
@@ -302,9 +443,9 @@ var validators = map[string]interface{}{
 			if context.value == nil{
 				return nil
 			}
-			if !regexp.MustCompile("^[0][9][0-9]{9}$").MatchString(context.value.(string)) {
+			if !mobile.MatchString(context.value.(string)) {
 				context.hasError = true
-				context.err = translate("string.mobile", translateAttribute(context.name))
+				context.err = context.translate("string.mobile", context.attribute(context.name))
 			}
 			return nil
 		},
@@ -312,9 +453,9 @@ var validators = map[string]interface{}{
 			if context.value == nil{
 				return nil
 			}
-			if !regexp.MustCompile("^[0][1-8][0-9]{9}$").MatchString(context.value.(string)) {
+			if !phone.MatchString(context.value.(string)) {
 				context.hasError = true
-				context.err = translate("string.phone", translateAttribute(context.name))
+				context.err = context.translate("string.phone", context.attribute(context.name))
 			}
 			return nil
 		},
@@ -328,14 +469,14 @@ var validators = map[string]interface{}{
 				}
 			}
 			context.hasError = true
-			context.err = translate("string.in", translateAttribute(context.name))
+			context.err = context.translate("string.in", context.attribute(context.name))
 			return nil
 		},
 		"inArray": func(context *phaseContext, obj subjectObj) error {
 			if context.value == nil{
 				return nil
 			}
-			val, ok := obj[context.args[0]]
+			val, ok := context.lookup(obj, context.args[0])
 			if ok && reflect.TypeOf(val).Kind() == reflect.Slice {
 				for _, item := range val.([]interface{}) {
 					if item == context.value {
@@ -343,7 +484,7 @@ var validators = map[string]interface{}{
 					}
 				}
 				context.hasError = true
-				context.err = translate("string.inArray", translateAttribute(context.name), translateAttribute(context.args[0]))
+				context.err = context.translate("string.inArray", context.attribute(context.name), context.attribute(context.args[0]))
 			}
 			return nil
 		},
@@ -354,75 +495,31 @@ var validators = map[string]interface{}{
 			for _, item := range context.args {
 				if item == context.value {
 					context.hasError = true
-					context.err = translate("string.notIn", translateAttribute(context.name))
+					context.err = context.translate("string.notIn", context.attribute(context.name))
 					return nil
 				}
 			}
 			return nil
 		},
-		"size": func(context *phaseContext, obj subjectObj) error {
-			if context.value == nil{
-				return nil
-			}
-			val, _ := strconv.Atoi(context.args[0])
-			str, ok :=context.value.(string)
-			if ok && len(str) != val {
-				context.hasError = true
-				context.err = translate("string.size", translateAttribute(context.name), val)
-				return nil
-			}
-			return nil
-		},
-		"min": func(context *phaseContext, obj subjectObj) error {
-			if context.value == nil{
-				return nil
-			}
-			a, _ := strconv.Atoi(context.args[0])
-			c := len(context.value.(string))
-			if c < a {
-				context.hasError = true
-				context.err = translate("string.min", translateAttribute(context.name), a)
-				return nil
-			}
-			return nil
-		},
-		"max": func(context *phaseContext, obj subjectObj) error {
-			if context.value == nil{
-				return nil
-			}
-			if context.value == nil{
-				return nil
-			}
-			b, _ := strconv.Atoi(context.args[0])
-			c := len(context.value.(string))
-			if c > b {
-				context.hasError = true
-				context.err = translate("string.max", translateAttribute(context.name), b)
-				return nil
-			}
-			return nil
-		},
-		"between": func(context *phaseContext, obj subjectObj) error {
-			if context.value == nil{
-				return nil
-			}
-			a, _ := strconv.Atoi(context.args[0])
-			b, _ := strconv.Atoi(context.args[1])
-			c := len(context.value.(string))
-			if c < a || c > b {
-				context.hasError = true
-				context.err = translate("string.between", translateAttribute(context.name), a, b)
-				return nil
-			}
-			return nil
-		},
+		"size": lengthRule("string.size", func(length int, limits []int) bool {
+			return length != limits[0]
+		}),
+		"min": lengthRule("string.min", func(length int, limits []int) bool {
+			return length < limits[0]
+		}),
+		"max": lengthRule("string.max", func(length int, limits []int) bool {
+			return length > limits[0]
+		}),
+		"between": lengthRule("string.between", func(length int, limits []int) bool {
+			return length < limits[0] || length > limits[1]
+		}),
 		"username": func(context *phaseContext, obj subjectObj) error {
 			if context.value == nil{
 				return nil
 			}
 			if !regexUsername.MatchString(context.value.(string)) {
 				context.hasError = true
-				context.err = translate("string.username", translateAttribute(context.name))
+				context.err = context.translate("string.username", context.attribute(context.name))
 				return nil
 			}
 			return nil
@@ -433,7 +530,7 @@ var validators = map[string]interface{}{
 			}
 			if !alphaNumeric.MatchString(context.value.(string)) {
 				context.hasError = true
-				context.err = translate("string.alphaNum", translateAttribute(context.name))
+				context.err = context.translate("string.alphaNum", context.attribute(context.name))
 				return nil
 			}
 			return nil
@@ -448,19 +545,19 @@ var validators = map[string]interface{}{
 			if hasFa && hasEn {
 				if !alphaPersian.MatchString(context.value.(string)) {
 					context.hasError = true
-					context.err = translate("string.alpha", translateAttribute(context.name))
+					context.err = context.translate("string.alpha", context.attribute(context.name))
 					return nil
 				}
 			} else if hasFa {
 				if !persian.MatchString(context.value.(string)) {
 					context.hasError = true
-					context.err = translate("string.persian", translateAttribute(context.name))
+					context.err = context.translate("string.persian", context.attribute(context.name))
 					return nil
 				}
 			} else if hasEn {
 				if !alpha.MatchString(context.value.(string)) {
 					context.hasError = true
-					context.err = translate("string.alpha", translateAttribute(context.name))
+					context.err = context.translate("string.alpha", context.attribute(context.name))
 					return nil
 				}
 			}
@@ -470,10 +567,10 @@ var validators = map[string]interface{}{
 			if context.value == nil{
 				return nil
 			}
-			re := regexp.MustCompile(context.args[0]).MatchString(context.value.(string))
+			re := context.prepared.(*regexp.Regexp).MatchString(context.value.(string))
 			if !re {
 				context.hasError = true
-				context.err = translate("string.regex", translateAttribute(context.name))
+				context.err = context.translate("string.regex", context.attribute(context.name))
 				return nil
 			}
 			return nil
@@ -482,10 +579,10 @@ var validators = map[string]interface{}{
 			if context.value == nil{
 				return nil
 			}
-			re := regexp.MustCompile(context.args[0]).MatchString(context.value.(string))
+			re := context.prepared.(*regexp.Regexp).MatchString(context.value.(string))
 			if re {
 				context.hasError = true
-				context.err = translate("string.regex", translateAttribute(context.name))
+				context.err = context.translate("string.regex", context.attribute(context.name))
 				return nil
 			}
 			return nil
@@ -501,7 +598,7 @@ var validators = map[string]interface{}{
 				}
 			}
 			context.hasError = true
-			context.err = translate("string.contains", translateAttribute(context.name), strings.Join(context.args, ","))
+			context.err = context.translate("string.contains", context.attribute(context.name), strings.Join(context.args, ","))
 			return nil
 		},
 		"startsWith": func(context *phaseContext, obj subjectObj) error {
@@ -515,7 +612,7 @@ var validators = map[string]interface{}{
 				}
 			}
 			context.hasError = true
-			context.err = translate("string.startsWith", translateAttribute(context.name), strings.Join(context.args, ","))
+			context.err = context.translate("string.startsWith", context.attribute(context.name), strings.Join(context.args, ","))
 			return nil
 		},
 		"endsWith": func(context *phaseContext, obj subjectObj) error {
@@ -530,7 +627,7 @@ var validators = map[string]interface{}{
 				}
 			}
 			context.hasError = true
-			context.err = translate("string.endsWith", translateAttribute(context.name), strings.Join(context.args, ","))
+			context.err = context.translate("string.endsWith", context.attribute(context.name), strings.Join(context.args, ","))
 			return nil
 		},
 		"same": func(context *phaseContext, obj subjectObj) error {
@@ -538,15 +635,14 @@ var validators = map[string]interface{}{
 				return nil
 			}
 			arg := context.args[0]
-			a, aOk := obj[context.name]
-			b, bOk := obj[arg]
-			if !aOk || !bOk {
+			b, ok := context.other(obj, arg)
+			if !ok {
 				context.hasError = true
-				context.err = translate("none", translateAttribute(context.name))
+				context.err = context.translate("none", context.attribute(context.name))
 			}
-			if a != b {
+			if context.value != b {
 				context.hasError = true
-				context.err = translate("same", translateAttribute(context.name), translateAttribute(arg))
+				context.err = context.translate("same", context.attribute(context.name), context.attribute(arg))
 			}
 			return nil
 		},
@@ -555,15 +651,14 @@ var validators = map[string]interface{}{
 				return nil
 			}
 			arg := context.args[0]
-			a, aOk := obj[context.name]
-			b, bOk := obj[arg]
-			if !aOk || !bOk {
+			b, ok := context.other(obj, arg)
+			if !ok {
 				context.hasError = true
-				context.err = translate("none", translateAttribute(context.name))
+				context.err = context.translate("none", context.attribute(context.name))
 			}
-			if a == b {
+			if context.value == b {
 				context.hasError = true
-				context.err = translate("different", translateAttribute(context.name), translateAttribute(arg))
+				context.err = context.translate("different", context.attribute(context.name), context.attribute(arg))
 			}
 			return nil
 		},